@@ -0,0 +1,189 @@
+// Command duperror runs the duplicate error message checker as a standalone
+// tool and renders the analyzer's exported result.
+//
+// Unlike the vet-compatible checker at the repository root it can print
+// reports beyond diagnostics, such as an inventory of every message.
+package main
+
+import (
+	"flag"
+	"fmt"
+	"io"
+	"log"
+	"os"
+
+	"github.com/adamdecaf/duperrormsg/duperrormsg"
+
+	"golang.org/x/tools/go/analysis"
+	"golang.org/x/tools/go/analysis/checker"
+	"golang.org/x/tools/go/packages"
+)
+
+var (
+	flagInventory  = flag.Bool("inventory", false, "Print every distinct message with its occurrence count and locations")
+	flagSort       = flag.String("sort", "", "Print duplicates grouped by message, sorted by frequency, position or message")
+	flagModule     = flag.Bool("module", false, "Find duplicates across every matched package, not just within each one")
+	flagCPUProfile = flag.String("cpuprofile", "", "Write a CPU profile of the analysis to this file")
+	flagMemProfile = flag.String("memprofile", "", "Write a memory profile to this file once the analysis is done")
+	flagSummaryFD  = flag.Int("summary-fd", 0, "Write a one line JSON summary of duplicates to this file descriptor, e.g. 3")
+)
+
+func main() {
+	log.SetFlags(0)
+	log.SetPrefix("duperror: ")
+
+	// Expose the analyzer's own flags alongside the command's
+	duperrormsg.Analyzer.Flags.VisitAll(func(f *flag.Flag) {
+		flag.Var(f.Value, f.Name, f.Usage)
+	})
+
+	flag.Usage = func() {
+		fmt.Fprintf(os.Stderr, "Usage: duperror [-flag] [package]\n\nFlags:\n")
+		flag.PrintDefaults()
+	}
+	flag.Parse()
+
+	if flag.NArg() == 0 {
+		flag.Usage()
+		os.Exit(1)
+	}
+
+	opts := options{
+		inventory: *flagInventory,
+		module:    *flagModule,
+		sort:      *flagSort,
+		summaryFD: *flagSummaryFD,
+	}
+	if err := opts.validate(); err != nil {
+		log.Print(err)
+		os.Exit(2)
+	}
+	if opts.summaryFD > 0 {
+		summaryFile = os.NewFile(uintptr(opts.summaryFD), "summary")
+		opts.summary = summaryFile
+	}
+	code, err := withProfiles(*flagCPUProfile, *flagMemProfile, func() int {
+		return run(opts, flag.Args(), os.Stdout)
+	})
+	if err != nil {
+		log.Print(err)
+		os.Exit(1)
+	}
+	os.Exit(code)
+}
+
+// summaryFile wraps the descriptor passed with -summary-fd. The descriptor
+// belongs to the caller, so it's never closed, and the file is held for the
+// life of the process since an unreachable *os.File closes its descriptor
+// once it's garbage collected.
+var summaryFile *os.File
+
+// options holds the command's rendering settings
+type options struct {
+	inventory bool
+	module    bool // Merge groups across packages
+	sort      string
+	summaryFD int       // Zero when no summary is written
+	summary   io.Writer // Receives the summary, nil when none is written
+}
+
+func (o options) validate() error {
+	if o.summaryFD < 0 {
+		return fmt.Errorf("invalid -summary-fd=%d", o.summaryFD)
+	}
+	switch o.sort {
+	case "", sortFrequency, sortPosition, sortMessage:
+		return nil
+	}
+	return fmt.Errorf("unknown -sort=%s, expected %s, %s or %s", o.sort, sortFrequency, sortPosition, sortMessage)
+}
+
+// packageResult is the outcome of analyzing one package
+type packageResult struct {
+	pkg         *packages.Package
+	result      *duperrormsg.Result
+	diagnostics []analysis.Diagnostic
+}
+
+// run analyzes the packages matching patterns and writes the report to stdout,
+// returning the process exit code.
+func run(opts options, patterns []string, stdout io.Writer) int {
+	results, err := analyze(patterns)
+	if err != nil {
+		log.Print(err)
+		return 1
+	}
+
+	if opts.summary != nil {
+		if err := writeSummary(opts.summary, collectGroups(results, true, opts.module)); err != nil {
+			log.Printf("writing summary: %v", err)
+			return 1
+		}
+	}
+
+	if opts.inventory {
+		writeGroups(stdout, sortGroups(collectGroups(results, false, opts.module), opts.sort))
+		return 0
+	}
+
+	// Diagnostics are reported per package, so module wide duplicates are
+	// listed as groups, by position unless another order was asked for
+	if opts.sort != "" || opts.module {
+		order := opts.sort
+		if order == "" {
+			order = sortPosition
+		}
+		groups := sortGroups(collectGroups(results, true, opts.module), order)
+		writeGroups(stdout, groups)
+		if len(groups) > 0 {
+			return 3
+		}
+		return 0
+	}
+
+	found := false
+	for _, res := range results {
+		for _, diag := range res.diagnostics {
+			fmt.Fprintf(stdout, "%s: %s\n", res.pkg.Fset.Position(diag.Pos), diag.Message)
+			for _, related := range diag.Related {
+				fmt.Fprintf(stdout, "\t%s: %s\n", res.pkg.Fset.Position(related.Pos), related.Message)
+			}
+			found = true
+		}
+	}
+	if found {
+		return 3
+	}
+	return 0
+}
+
+func analyze(patterns []string) ([]packageResult, error) {
+	cfg := &packages.Config{
+		Mode: packages.LoadAllSyntax,
+	}
+	pkgs, err := packages.Load(cfg, patterns...)
+	if err != nil {
+		return nil, err
+	}
+	if n := packages.PrintErrors(pkgs); n > 0 {
+		return nil, fmt.Errorf("%d errors loading packages", n)
+	}
+
+	graph, err := checker.Analyze([]*analysis.Analyzer{duperrormsg.Analyzer}, pkgs, nil)
+	if err != nil {
+		return nil, err
+	}
+
+	var results []packageResult
+	for _, act := range graph.Roots {
+		if act.Err != nil {
+			return nil, fmt.Errorf("analyzing %s: %w", act.Package.PkgPath, act.Err)
+		}
+		results = append(results, packageResult{
+			pkg:         act.Package,
+			result:      act.Result.(*duperrormsg.Result),
+			diagnostics: act.Diagnostics,
+		})
+	}
+	return results, nil
+}
@@ -0,0 +1,272 @@
+package main
+
+import (
+	"bytes"
+	"io"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/adamdecaf/duperrormsg/duperrormsg"
+)
+
+func TestInventory(t *testing.T) {
+	dir, err := filepath.Abs(filepath.Join("testdata", "inventory"))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	var buf bytes.Buffer
+	if code := run(options{inventory: true}, []string{"./testdata/inventory"}, &buf); code != 0 {
+		t.Fatalf("unexpected exit code %d", code)
+	}
+
+	got := strings.ReplaceAll(buf.String(), dir+string(filepath.Separator), "")
+	want := strings.Join([]string{
+		`"missing name" (2 locations)`,
+		"\tinventory.go:10:10 (errors.New)",
+		"\tinventory.go:15:9 (errors.New)",
+		`"name %q too long" (1 location)`,
+		"\tinventory.go:13:10 (fmt.Errorf)",
+		"",
+	}, "\n")
+	if got != want {
+		t.Errorf("unexpected inventory\ngot:\n%s\nwant:\n%s", got, want)
+	}
+}
+
+func TestInventoryFiles(t *testing.T) {
+	dir, err := filepath.Abs(filepath.Join("testdata", "spread"))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	var buf bytes.Buffer
+	if code := run(options{inventory: true}, []string{"./testdata/spread"}, &buf); code != 0 {
+		t.Fatalf("unexpected exit code %d", code)
+	}
+
+	// Messages used in several files note how many
+	got := strings.ReplaceAll(buf.String(), dir+string(filepath.Separator), "")
+	want := strings.Join([]string{
+		`"missing name" (2 locations in 2 files)`,
+		"\ta.go:7:10 (errors.New)",
+		"\tb.go:7:10 (errors.New)",
+		`"not supported" (2 locations)`,
+		"\ta.go:9:9 (errors.New)",
+		"\ta.go:13:9 (errors.New)",
+		"",
+	}, "\n")
+	if got != want {
+		t.Errorf("unexpected inventory\ngot:\n%s\nwant:\n%s", got, want)
+	}
+}
+
+func TestModule(t *testing.T) {
+	dir, err := filepath.Abs(filepath.Join("testdata", "module"))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// Each package on its own only repeats "missing id"
+	var buf bytes.Buffer
+	if code := run(options{}, []string{"./testdata/module/..."}, &buf); code != 3 {
+		t.Fatalf("unexpected exit code %d", code)
+	}
+	if strings.Contains(buf.String(), "account not found") {
+		t.Errorf("unexpected duplicate across packages without -module:\n%s", buf.String())
+	}
+
+	// Messages used once in each of several packages are found module wide
+	missing := []string{
+		`"missing id" (2 locations)`,
+		"\taccounts/accounts.go:10:10 (errors.New)",
+		"\taccounts/accounts.go:17:10 (errors.New)",
+	}
+	notFound := []string{
+		`"account not found" (2 locations in 2 files)`,
+		"\taccounts/accounts.go:12:9 (errors.New)",
+		"\tbilling/billing.go:9:9 (errors.New)",
+	}
+	paymentFailed := []string{
+		`"payment failed" (2 locations in 2 files)`,
+		"\taccounts/accounts.go:23:2 (log)",
+		"\tbilling/billing.go:13:9 (errors.New)",
+	}
+	cases := []struct {
+		name  string
+		flags map[string]string
+		want  [][]string
+	}{
+		{name: "package", want: [][]string{missing, notFound, paymentFailed}},
+
+		// Groups the analyzer kept apart aren't merged back together
+		{name: "file", flags: map[string]string{"scope": "file"}, want: [][]string{missing}},
+		{name: "kind", flags: map[string]string{"separate-by-kind": "true"}, want: [][]string{missing, notFound}},
+	}
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			for name, value := range tc.flags {
+				f := duperrormsg.Analyzer.Flags.Lookup(name)
+				if err := f.Value.Set(value); err != nil {
+					t.Fatal(err)
+				}
+				defer f.Value.Set(f.DefValue)
+			}
+
+			var buf bytes.Buffer
+			if code := run(options{module: true}, []string{"./testdata/module/..."}, &buf); code != 3 {
+				t.Fatalf("unexpected exit code %d", code)
+			}
+			var lines []string
+			for _, group := range tc.want {
+				lines = append(lines, group...)
+			}
+			want := strings.Join(lines, "\n") + "\n"
+			if got := strings.ReplaceAll(buf.String(), dir+string(filepath.Separator), ""); got != want {
+				t.Errorf("unexpected module duplicates\ngot:\n%s\nwant:\n%s", got, want)
+			}
+		})
+	}
+}
+
+func TestDiagnosticsOrder(t *testing.T) {
+	dir, err := filepath.Abs(filepath.Join("testdata", "ordering"))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	var buf bytes.Buffer
+	if code := run(options{}, []string{"./testdata/ordering"}, &buf); code != 3 {
+		t.Fatalf("unexpected exit code %d", code)
+	}
+
+	// Messages are reported alphabetically, each starting at its earliest position
+	got := strings.ReplaceAll(buf.String(), dir+string(filepath.Separator), "")
+	want := strings.Join([]string{
+		`ordering.go:11:9: duplicate error message "access denied" used at 2 locations (errors.New)`,
+		`	ordering.go:15:12: also used here (errors.New)`,
+		`ordering.go:8:12: duplicate error message "timed out" used at 2 locations (errors.New)`,
+		`	ordering.go:18:9: also used here (errors.New)`,
+		"",
+	}, "\n")
+	if got != want {
+		t.Errorf("unexpected diagnostics\ngot:\n%s\nwant:\n%s", got, want)
+	}
+}
+
+func TestSort(t *testing.T) {
+	dir, err := filepath.Abs(filepath.Join("testdata", "sorting"))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	quota := []string{`"quota exceeded" (2 locations)`, "\tsorting.go:8:9 (errors.New)", "\tsorting.go:10:9 (errors.New)"}
+	bad := []string{`"bad request" (2 locations)`, "\tsorting.go:9:9 (errors.New)", "\tsorting.go:12:9 (errors.New)"}
+	unavailable := []string{`"unavailable" (3 locations)`, "\tsorting.go:11:9 (errors.New)", "\tsorting.go:13:9 (errors.New)", "\tsorting.go:14:9 (errors.New)"}
+
+	cases := []struct {
+		order string
+		want  [][]string
+	}{
+		{order: "frequency", want: [][]string{unavailable, bad, quota}},
+		{order: "position", want: [][]string{quota, bad, unavailable}},
+		{order: "message", want: [][]string{bad, quota, unavailable}},
+	}
+	for _, tc := range cases {
+		t.Run(tc.order, func(t *testing.T) {
+			var buf bytes.Buffer
+			if code := run(options{sort: tc.order}, []string{"./testdata/sorting"}, &buf); code != 3 {
+				t.Fatalf("unexpected exit code %d", code)
+			}
+
+			var lines []string
+			for _, group := range tc.want {
+				lines = append(lines, group...)
+			}
+			want := strings.Join(lines, "\n") + "\n"
+
+			got := strings.ReplaceAll(buf.String(), dir+string(filepath.Separator), "")
+			if got != want {
+				t.Errorf("unexpected report\ngot:\n%s\nwant:\n%s", got, want)
+			}
+		})
+	}
+
+	if err := (options{sort: "size"}).validate(); err == nil {
+		t.Error("expected an error for an unknown order")
+	}
+
+	// Groups are filtered like diagnostics, here by the threshold
+	threshold := duperrormsg.Analyzer.Flags.Lookup("threshold")
+	if err := threshold.Value.Set("3"); err != nil {
+		t.Fatal(err)
+	}
+	defer threshold.Value.Set(threshold.DefValue)
+
+	var buf bytes.Buffer
+	if code := run(options{sort: sortFrequency}, []string{"./testdata/sorting"}, &buf); code != 3 {
+		t.Fatalf("unexpected exit code %d", code)
+	}
+	want := strings.Join(unavailable, "\n") + "\n"
+	if got := strings.ReplaceAll(buf.String(), dir+string(filepath.Separator), ""); got != want {
+		t.Errorf("unexpected report with -threshold=3\ngot:\n%s\nwant:\n%s", got, want)
+	}
+}
+
+func TestSummaryFD(t *testing.T) {
+	r, w, err := os.Pipe()
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer r.Close()
+
+	var buf bytes.Buffer
+	code := run(options{summary: w}, []string{"./testdata/sorting"}, &buf)
+	w.Close()
+	if code != 3 {
+		t.Fatalf("unexpected exit code %d", code)
+	}
+
+	got, err := io.ReadAll(r)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if want := `{"groups":3,"sites":7}` + "\n"; string(got) != want {
+		t.Errorf("unexpected summary %q, want %q", got, want)
+	}
+
+	// Diagnostics are still written as usual
+	if !strings.Contains(buf.String(), `duplicate error message "unavailable" used by errD, errF and errG`) {
+		t.Errorf("missing diagnostics:\n%s", buf.String())
+	}
+
+	if err := (options{summaryFD: -1}).validate(); err == nil {
+		t.Error("expected an error for a negative descriptor")
+	}
+}
+
+func TestProfiles(t *testing.T) {
+	dir := t.TempDir()
+	cpu, mem := filepath.Join(dir, "cpu.pprof"), filepath.Join(dir, "mem.pprof")
+
+	code, err := withProfiles(cpu, mem, func() int {
+		return run(options{inventory: true}, []string{"./testdata/inventory"}, io.Discard)
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if code != 0 {
+		t.Fatalf("unexpected exit code %d", code)
+	}
+	for _, name := range []string{cpu, mem} {
+		info, err := os.Stat(name)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if info.Size() == 0 {
+			t.Errorf("%s is empty", filepath.Base(name))
+		}
+	}
+}
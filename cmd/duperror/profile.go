@@ -0,0 +1,43 @@
+package main
+
+import (
+	"fmt"
+	"os"
+	"runtime"
+	"runtime/pprof"
+)
+
+// withProfiles runs f, writing a CPU profile of it to cpuFile and a heap
+// profile taken afterwards to memFile. Either may be empty to skip it.
+func withProfiles(cpuFile, memFile string, f func() int) (int, error) {
+	if cpuFile != "" {
+		out, err := os.Create(cpuFile)
+		if err != nil {
+			return 0, fmt.Errorf("creating CPU profile: %w", err)
+		}
+		defer out.Close()
+		if err := pprof.StartCPUProfile(out); err != nil {
+			return 0, fmt.Errorf("starting CPU profile: %w", err)
+		}
+	}
+
+	code := f()
+
+	if cpuFile != "" {
+		pprof.StopCPUProfile()
+	}
+	if memFile != "" {
+		out, err := os.Create(memFile)
+		if err != nil {
+			return code, fmt.Errorf("creating memory profile: %w", err)
+		}
+		defer out.Close()
+
+		// Collect garbage first so the profile shows live memory
+		runtime.GC()
+		if err := pprof.WriteHeapProfile(out); err != nil {
+			return code, fmt.Errorf("writing memory profile: %w", err)
+		}
+	}
+	return code, nil
+}
@@ -0,0 +1,113 @@
+package main
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"sort"
+
+	"github.com/adamdecaf/duperrormsg/duperrormsg"
+)
+
+// Orders accepted by -sort
+const (
+	sortFrequency = "frequency"
+	sortPosition  = "position"
+	sortMessage   = "message"
+)
+
+// collectGroups returns the groups from every package, optionally keeping
+// only duplicated messages. With module set, groups sharing a message are
+// merged first, so messages used once in each of several packages count as
+// duplicates too.
+func collectGroups(results []packageResult, duplicatesOnly, module bool) []*duperrormsg.Group {
+	var groups []*duperrormsg.Group
+	for _, res := range results {
+		if duplicatesOnly && !module {
+			groups = append(groups, res.result.Duplicates()...)
+		} else {
+			groups = append(groups, res.result.Groups...)
+		}
+	}
+	if !module {
+		return groups
+	}
+
+	merged := duperrormsg.MergeGroups(groups)
+	if !duplicatesOnly {
+		return merged
+	}
+	var out []*duperrormsg.Group
+	for _, g := range merged {
+		if g.IsDuplicate() {
+			out = append(out, g)
+		}
+	}
+	return out
+}
+
+// sortGroups orders groups in place according to order, leaving them
+// untouched when order is empty.
+func sortGroups(groups []*duperrormsg.Group, order string) []*duperrormsg.Group {
+	switch order {
+	case sortFrequency:
+		// Most duplicated first, ties broken by the message
+		sort.SliceStable(groups, func(i, j int) bool {
+			if a, b := len(groups[i].Occurrences), len(groups[j].Occurrences); a != b {
+				return a > b
+			}
+			return groups[i].Text < groups[j].Text
+		})
+
+	case sortPosition:
+		sort.SliceStable(groups, func(i, j int) bool {
+			return positionLess(groups[i].Occurrences[0], groups[j].Occurrences[0])
+		})
+
+	case sortMessage:
+		sort.SliceStable(groups, func(i, j int) bool {
+			if groups[i].Text != groups[j].Text {
+				return groups[i].Text < groups[j].Text
+			}
+			return positionLess(groups[i].Occurrences[0], groups[j].Occurrences[0])
+		})
+	}
+	return groups
+}
+
+func positionLess(a, b duperrormsg.Occurrence) bool {
+	if a.Pos.Filename != b.Pos.Filename {
+		return a.Pos.Filename < b.Pos.Filename
+	}
+	if a.Pos.Line != b.Pos.Line {
+		return a.Pos.Line < b.Pos.Line
+	}
+	return a.Pos.Column < b.Pos.Column
+}
+
+// writeGroups prints each message along with how often and where it was
+// used, counted the way the analyzer's diagnostics count them.
+func writeGroups(w io.Writer, groups []*duperrormsg.Group) {
+	for _, group := range groups {
+		fmt.Fprintf(w, "%q (%s)\n", group.Text, duperrormsg.CountLocations(len(group.Occurrences), group.Files()))
+		for _, occ := range group.Occurrences {
+			fmt.Fprintf(w, "\t%s (%s)\n", occ.Pos, occ.Construct)
+		}
+	}
+}
+
+// summary is the machine readable outcome written by -summary-fd
+type summary struct {
+	Groups int `json:"groups"` // Duplicated messages
+	Sites  int `json:"sites"`  // Occurrences of those messages
+}
+
+// writeSummary writes the number of duplicated messages and their
+// occurrences as a single line of JSON.
+func writeSummary(w io.Writer, duplicates []*duperrormsg.Group) error {
+	s := summary{Groups: len(duplicates)}
+	for _, group := range duplicates {
+		s.Sites += len(group.Occurrences)
+	}
+	return json.NewEncoder(w).Encode(s)
+}
@@ -0,0 +1,16 @@
+package inventory
+
+import (
+	"errors"
+	"fmt"
+)
+
+func load(name string) error {
+	if name == "" {
+		return errors.New("missing name")
+	}
+	if len(name) > 64 {
+		return fmt.Errorf("name %q too long", name)
+	}
+	return errors.New("missing name")
+}
@@ -0,0 +1,24 @@
+package accounts
+
+import (
+	"errors"
+	"log"
+)
+
+func open(id string) error {
+	if id == "" {
+		return errors.New("missing id")
+	}
+	return errors.New("account not found")
+}
+
+func close(id string) error {
+	if id == "" {
+		return errors.New("missing id")
+	}
+	return nil
+}
+
+func settle(id string) {
+	log.Printf("payment failed")
+}
@@ -0,0 +1,14 @@
+package billing
+
+import "errors"
+
+func charge(amount int) error {
+	if amount <= 0 {
+		return errors.New("invalid amount")
+	}
+	return errors.New("account not found")
+}
+
+func refund(amount int) error {
+	return errors.New("payment failed")
+}
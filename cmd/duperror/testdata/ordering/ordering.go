@@ -0,0 +1,19 @@
+package ordering
+
+import (
+	"errors"
+)
+
+func open() error {
+	if err := errors.New("timed out"); err != nil {
+		return err
+	}
+	return errors.New("access denied")
+}
+
+func close() error {
+	if err := errors.New("access denied"); err != nil {
+		return err
+	}
+	return errors.New("timed out")
+}
@@ -0,0 +1,16 @@
+package sorting
+
+import (
+	"errors"
+)
+
+var (
+	errA = errors.New("quota exceeded")
+	errB = errors.New("bad request")
+	errC = errors.New("quota exceeded")
+	errD = errors.New("unavailable")
+	errE = errors.New("bad request")
+	errF = errors.New("unavailable")
+	errG = errors.New("unavailable")
+	errH = errors.New("only once")
+)
@@ -0,0 +1,10 @@
+package spread
+
+import "errors"
+
+func create(name string) error {
+	if name == "" {
+		return errors.New("missing name")
+	}
+	return nil
+}
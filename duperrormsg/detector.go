@@ -0,0 +1,17 @@
+package duperrormsg
+
+import (
+	"go/ast"
+
+	"golang.org/x/tools/go/analysis"
+)
+
+// ConstructDetector recognizes error constructs the analyzer doesn't know
+// about. Detect reports whether call constructs an error and, if so, the
+// construct's name as shown in results, the expression holding its message
+// and whether that message is a printf-style format string.
+//
+// Detect may be called concurrently from multiple goroutines.
+type ConstructDetector interface {
+	Detect(call *ast.CallExpr, pass *analysis.Pass) (name string, msgExpr ast.Expr, isFormat bool, ok bool)
+}
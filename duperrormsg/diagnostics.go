@@ -0,0 +1,306 @@
+package duperrormsg
+
+import (
+	"fmt"
+	"go/ast"
+	"go/token"
+	"sort"
+	"strings"
+
+	"golang.org/x/tools/go/analysis"
+)
+
+// Categories of the diagnostics reported
+const (
+	CategoryDuplicate = "duplicate" // Identical messages
+	CategorySimilar   = "similar"   // Messages grouped by Similarity
+	CategoryImported  = "imported"  // Messages also used by an imported package
+
+	// Substrings expected of errors in more than one test, with IncludeTestify
+	CategoryTestifyExpect = "testify-expect"
+
+	// Counts of the duplicates in a package, with Summary
+	CategorySummary = "summary"
+
+	// Messages repeating a standard library error, with FlagStdlibCollisions
+	CategoryStdlib = "stdlib"
+
+	// Messages used fewer times than the threshold, with ReportSingletons
+	CategoryInventory = "inventory"
+
+	// Tails after ": " shared by different messages, with SplitOnColon
+	CategoryTail = "tail"
+)
+
+// DiagnosticsFor returns the duplicate diagnostics the Analyzer reports for
+// result, formatted and limited according to opts, so embedders can render
+// them without running the analysis again. Messages also used by imported
+// packages depend on facts and aren't included. The result must come from
+// the Analyzer, nil is returned for any other. Options the Analyzer would
+// reject, such as a threshold below 2, return an error.
+func DiagnosticsFor(result *Result, opts Options) ([]analysis.Diagnostic, error) {
+	opts.applyDefaults()
+	if err := opts.validate(); err != nil {
+		return nil, err
+	}
+	diags, _ := result.diagnose(&opts)
+	return diags, nil
+}
+
+// diagnose builds a diagnostic, and the matching finding, for every
+// duplicated message in r
+func (r *Result) diagnose(opts *Options) ([]analysis.Diagnostic, []Finding) {
+	if r.pass == nil {
+		return nil, nil
+	}
+	fset := r.pass.Fset
+	fixes := newFixer(r.pass)
+
+	var diags []analysis.Diagnostic
+	var findings []Finding
+	seen := make(reported)
+	var collisions map[string]string
+	if opts.FlagStdlibCollisions {
+		collisions = opts.withPipeline().stdlibCollisions()
+	}
+	for _, key := range r.keys {
+		locations := r.errorMap[key]
+
+		// Errors repeating the text of a standard library error are flagged
+		// wherever they're constructed, even once
+		if source, ok := collisions[key.msg]; ok && key.kind != kindExpect {
+			for _, loc := range locations {
+				if loc.kind != kindError {
+					continue
+				}
+				diag := analysis.Diagnostic{
+					Pos:      loc.reportPos(),
+					Category: CategoryStdlib,
+					Message:  fmt.Sprintf("error message %q matches the text of %s, return, wrap or compare against it instead", loc.Text, source),
+				}
+				if seen.add(diag, opts) {
+					diags = append(diags, diag)
+					findings = append(findings, newFinding(r.pass, diag, []ErrorInfo{loc}))
+				}
+			}
+		}
+
+		if len(locations) < opts.Threshold {
+			// Messages that aren't duplicates are listed for inventories
+			if opts.ReportSingletons && key.kind != kindExpect {
+				diag := analysis.Diagnostic{
+					Pos:      locations[0].reportPos(),
+					Category: CategoryInventory,
+					Message:  fmt.Sprintf("error message %q used at %s", locations[0].Text, plural(len(locations), "location")),
+				}
+				if seen.add(diag, opts) {
+					diags = append(diags, diag)
+				}
+			}
+			continue
+		}
+		if opts.SkipIfAllInOneFunc && inOneFunc(locations) {
+			opts.debugf("%s: skipping %q used only within one function", fset.Position(locations[0].reportPos()), locations[0].Text)
+			continue
+		}
+
+		// Report one finding at the first occurrence, naming the variables
+		// when every occurrence declares a sentinel error
+		firstLoc := locations[0]
+		count := CountLocations(len(locations), fileCount(fset, locations))
+		constructs := fmt.Sprintf(" (%s)", constructsOf(locations))
+		diag := analysis.Diagnostic{
+			Pos:      firstLoc.reportPos(),
+			Category: CategoryDuplicate,
+			Message:  fmt.Sprintf("duplicate error message %q used at %s", firstLoc.Text, count),
+		}
+		if names := sentinelNames(locations); names != "" {
+			diag.Message = fmt.Sprintf("duplicate error message %q used by %s", firstLoc.Text, names)
+		}
+		diag.Message += constructs
+		if key.kind == kindExpect {
+			diag.Category = CategoryTestifyExpect
+			diag.Message = fmt.Sprintf("duplicate expected error substring %q used at %s%s", firstLoc.Text, count, constructs)
+		}
+
+		// Similar messages show every spelling so one can be picked
+		similar := r.spellings[key]
+		if len(similar) > 1 {
+			diag.Category = CategorySimilar
+			diag.Message = fmt.Sprintf("similar error messages %s used at %s%s", quotedList(similar), count, constructs)
+		}
+
+		// Repeats within one function are most likely copied and pasted
+		if opts.HighlightCopyPaste && sharesFunc(locations) {
+			diag.Message = "likely copy-paste: " + diag.Message
+		}
+
+		// Calls chained onto a constructor, as in NewErr("a").Log("b"), start
+		// at the same position, so two groups can be reported at one site
+		if !seen.add(diag, opts) {
+			continue
+		}
+
+		// Every other occurrence is attached to it as related information,
+		// up to the limit per message
+		others := locations[1:]
+		if limit := opts.LimitPerMessage; limit > 0 && len(others) > limit {
+			diag.Message += fmt.Sprintf(" ...and %d more", len(others)-limit)
+			others = others[:limit]
+		}
+		for _, loc := range others {
+			diag.Related = append(diag.Related, analysis.RelatedInformation{
+				Pos:     loc.reportPos(),
+				Message: fmt.Sprintf("also used here (%s)", loc.Construct),
+			})
+		}
+		if fix, ok := fixes.extractVar(locations); ok && len(similar) == 0 {
+			diag.SuggestedFixes = []analysis.SuggestedFix{fix}
+		}
+		diags = append(diags, diag)
+		findings = append(findings, newFinding(r.pass, diag, locations))
+	}
+
+	// Tails shared by messages with different prefixes are reported apart
+	if opts.SplitOnColon {
+		for _, diag := range r.diagnoseTails(opts) {
+			if seen.add(diag.Diagnostic, opts) {
+				diags = append(diags, diag.Diagnostic)
+				findings = append(findings, newFinding(r.pass, diag.Diagnostic, diag.locations))
+			}
+		}
+	}
+	return diags, findings
+}
+
+// tailKey groups messages by what follows their last ": "
+type tailKey struct {
+	tail  string
+	scope ast.Node
+	kind  string
+}
+
+// tailDiagnostic is a diagnostic for a shared tail along with its locations
+type tailDiagnostic struct {
+	analysis.Diagnostic
+	locations []ErrorInfo
+}
+
+// diagnoseTails reports the tails after the last ": " shared by different
+// messages, as in "reading config: file not found" and "reading secrets:
+// file not found". Tails without any text, such as "%w", are left alone.
+func (r *Result) diagnoseTails(opts *Options) []tailDiagnostic {
+	fset := r.pass.Fset
+	byTail := make(map[tailKey][]groupKey)
+	var tails []tailKey
+	for _, key := range r.keys {
+		idx := strings.LastIndex(key.msg, ": ")
+		if idx < 0 || !hasText(key.msg[idx+2:]) {
+			continue
+		}
+		tk := tailKey{tail: key.msg[idx+2:], scope: key.scope, kind: key.kind}
+		if _, ok := byTail[tk]; !ok {
+			tails = append(tails, tk)
+		}
+		byTail[tk] = append(byTail[tk], key)
+	}
+
+	var out []tailDiagnostic
+	for _, tk := range tails {
+		keys := byTail[tk]
+		if len(keys) < 2 {
+			continue
+		}
+		var locations []ErrorInfo
+		for _, key := range keys {
+			locations = append(locations, r.errorMap[key]...)
+		}
+		sort.Slice(locations, func(i, j int) bool {
+			return posLess(fset, locations[i].Pos.Pos(), locations[j].Pos.Pos())
+		})
+
+		// The tail is shown as first written where it can be found
+		first := locations[0]
+		tail := tk.tail
+		if idx := strings.LastIndex(first.Text, ": "); idx >= 0 {
+			tail = first.Text[idx+2:]
+		}
+		diag := analysis.Diagnostic{
+			Pos:      first.reportPos(),
+			Category: CategoryTail,
+			Message:  fmt.Sprintf("duplicate error tail %q across differently-prefixed messages", tail),
+		}
+		for _, loc := range locations[1:] {
+			diag.Related = append(diag.Related, analysis.RelatedInformation{
+				Pos:     loc.reportPos(),
+				Message: fmt.Sprintf("also used here as %q", loc.Text),
+			})
+		}
+		out = append(out, tailDiagnostic{Diagnostic: diag, locations: locations})
+	}
+	return out
+}
+
+// summarize counts the duplicated messages and their occurrences in findings.
+// Standard library collisions aren't duplicates, and shared tails span
+// messages already counted on their own, so neither is counted.
+// There's no position for a package as a whole, so it's reported at the
+// package clause of the first file.
+func summarize(pass *analysis.Pass, findings []Finding) (analysis.Diagnostic, bool) {
+	groups, sites := 0, 0
+	for _, f := range findings {
+		if f.Category == CategoryStdlib || f.Category == CategoryTail {
+			continue
+		}
+		groups++
+		sites += len(f.Duplicates) + 1
+	}
+	if groups == 0 || len(pass.Files) == 0 {
+		return analysis.Diagnostic{}, false
+	}
+	return analysis.Diagnostic{
+		Pos:      pass.Files[0].Package,
+		Category: CategorySummary,
+		Message:  fmt.Sprintf("found %d duplicate message groups covering %d call sites", groups, sites),
+	}, true
+}
+
+// reported records the sites already holding a diagnostic of each category
+type reported map[reportedKey]bool
+
+type reportedKey struct {
+	pos      token.Pos
+	category string
+}
+
+// add records diag and reports whether it should be emitted, which is
+// always the case with ReportEveryGroup and otherwise only for the first
+// diagnostic of its category at its position
+func (r reported) add(diag analysis.Diagnostic, opts *Options) bool {
+	key := reportedKey{pos: diag.Pos, category: diag.Category}
+	if r[key] && !opts.ReportEveryGroup {
+		opts.debugf("skipping %q, another %s diagnostic is reported at the same position", diag.Message, diag.Category)
+		return false
+	}
+	r[key] = true
+	return true
+}
+
+// constructsOf names the construct shared by every location, or returns
+// "mixed constructs" when they differ
+func constructsOf(locations []ErrorInfo) string {
+	for _, loc := range locations[1:] {
+		if loc.Construct != locations[0].Construct {
+			return "mixed constructs"
+		}
+	}
+	return locations[0].Construct
+}
+
+// plural formats n items, as in "1 location" or "2 locations"
+func plural(n int, item string) string {
+	if n == 1 {
+		return fmt.Sprintf("1 %s", item)
+	}
+	return fmt.Sprintf("%d %ss", n, item)
+}
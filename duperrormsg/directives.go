@@ -0,0 +1,152 @@
+package duperrormsg
+
+import (
+	"go/ast"
+	"regexp"
+	"strings"
+
+	"golang.org/x/tools/go/analysis"
+)
+
+// suppressions records the lines, per file, where findings are suppressed
+type suppressions map[string]map[int]bool
+
+// findSuppressions collects every line carrying a trailing //nolint:duperror
+// comment, optionally followed by a reason as in //nolint:duperror // reason,
+// along with the lines below staticcheck style //lint:ignore duperror reason
+// directives.
+func findSuppressions(pass *analysis.Pass) suppressions {
+	out := make(suppressions)
+	for _, file := range pass.Files {
+		for _, group := range file.Comments {
+			for _, c := range group.List {
+				pos := pass.Fset.Position(c.Pos())
+				line := pos.Line
+				switch {
+				case isNolint(c.Text):
+				case isLintIgnore(c.Text):
+					line++
+				default:
+					continue
+				}
+				if out[pos.Filename] == nil {
+					out[pos.Filename] = make(map[int]bool)
+				}
+				out[pos.Filename][line] = true
+			}
+		}
+	}
+	return out
+}
+
+// findGenerated returns the names of files carrying the standard
+// "// Code generated ... DO NOT EDIT." marker before their package clause.
+func findGenerated(pass *analysis.Pass) map[string]bool {
+	out := make(map[string]bool)
+	for _, file := range pass.Files {
+		if ast.IsGenerated(file) {
+			out[pass.Fset.Position(file.Pos()).Filename] = true
+		}
+	}
+	return out
+}
+
+// stringerName matches the names of the tables stringer generates for a
+// type T, _T_name and _T_index, or _T_name_0 for types with several runs
+var stringerName = regexp.MustCompile(`^_(\w+)_(name|index)(_\d+)?$`)
+
+// findStringer returns the names of files generated by stringer, carrying
+// its "// Code generated by "stringer ..."" marker, or still declaring both
+// of the _T_name and _T_index tables it emits after being edited by hand.
+func findStringer(pass *analysis.Pass) map[string]bool {
+	out := make(map[string]bool)
+	for _, file := range pass.Files {
+		if isStringer(file) {
+			out[pass.Fset.Position(file.Pos()).Filename] = true
+		}
+	}
+	return out
+}
+
+func isStringer(file *ast.File) bool {
+	for _, group := range file.Comments {
+		if group.Pos() > file.Package {
+			break
+		}
+		for _, c := range group.List {
+			if strings.HasPrefix(c.Text, `// Code generated by "stringer`) {
+				return true
+			}
+		}
+	}
+
+	tables := make(map[string]map[string]bool)
+	for _, decl := range file.Decls {
+		gen, ok := decl.(*ast.GenDecl)
+		if !ok {
+			continue
+		}
+		for _, spec := range gen.Specs {
+			value, ok := spec.(*ast.ValueSpec)
+			if !ok {
+				continue
+			}
+			for _, name := range value.Names {
+				m := stringerName.FindStringSubmatch(name.Name)
+				if m == nil {
+					continue
+				}
+				if tables[m[1]] == nil {
+					tables[m[1]] = make(map[string]bool)
+				}
+				tables[m[1]][m[2]] = true
+				if tables[m[1]]["name"] && tables[m[1]]["index"] {
+					return true
+				}
+			}
+		}
+	}
+	return false
+}
+
+// isNolint reports whether a comment is a nolint directive naming this analyzer
+func isNolint(text string) bool {
+	linters, ok := strings.CutPrefix(text, "//nolint:")
+	if !ok {
+		return false
+	}
+	if idx := strings.IndexAny(linters, " \t"); idx >= 0 {
+		linters = linters[:idx]
+	}
+	for _, linter := range strings.Split(linters, ",") {
+		if linter == "duperror" {
+			return true
+		}
+	}
+	return false
+}
+
+// isLintIgnore reports whether a comment is a staticcheck style
+// //lint:ignore directive naming this analyzer. As with staticcheck, the
+// comma separated checks have to be followed by a reason.
+func isLintIgnore(text string) bool {
+	rest, ok := strings.CutPrefix(text, "//lint:ignore ")
+	if !ok {
+		return false
+	}
+	fields := strings.Fields(rest)
+	if len(fields) < 2 {
+		return false
+	}
+	for _, check := range strings.Split(fields[0], ",") {
+		if check == "duperror" {
+			return true
+		}
+	}
+	return false
+}
+
+// suppressed reports whether a finding at file:line has been suppressed
+func (s suppressions) suppressed(file string, line int) bool {
+	return s[file][line]
+}
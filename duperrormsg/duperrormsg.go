@@ -2,8 +2,13 @@ package duperrormsg
 
 import (
 	"go/ast"
+	"go/constant"
 	"go/token"
-	"regexp"
+	"go/types"
+	"net/http"
+	"reflect"
+	"sort"
+	"strconv"
 	"strings"
 
 	"golang.org/x/tools/go/analysis"
@@ -12,22 +17,112 @@ import (
 )
 
 // Analyzer is the main analyzer for the duplicate-error checker
-var Analyzer = &analysis.Analyzer{
-	Name:     "duperror",
-	Doc:      "Checks for duplicate error messages across different code paths",
-	Run:      run,
-	Requires: []*analysis.Analyzer{inspect.Analyzer},
+var Analyzer = NewAnalyzer(Options{})
+
+// NewAnalyzer returns a duplicate-error checker configured with opts.
+// The analyzer's flags start out with the values from opts.
+func NewAnalyzer(opts Options) *analysis.Analyzer {
+	opts.applyDefaults()
+	a := &analysis.Analyzer{
+		Name: "duperror",
+		Doc:  "Checks for duplicate error messages across different code paths",
+		Run: func(pass *analysis.Pass) (interface{}, error) {
+			return run(pass, &opts)
+		},
+		Requires: []*analysis.Analyzer{inspect.Analyzer},
+
+		ResultType: reflect.TypeOf((*Result)(nil)),
+		FactTypes:  factTypes(opts.CrossPackage),
+	}
+	opts.registerFlags(&a.Flags)
+	a.Flags.Var(&crossPackageFlag{opts: &opts, analyzer: a}, "cross-package", "also report messages used by directly imported packages")
+	return a
 }
 
 // ErrorInfo stores information about an error message
 type ErrorInfo struct {
-	Pos       ast.Node // Position in source
-	Construct string   // Which error construction method was used
+	Pos       ast.Node   // Position in source
+	Construct string     // Which error construction method was used
+	Text      string     // Message as written, before normalization
+	Func      ast.Node   // Enclosing *ast.FuncDecl or *ast.FuncLit, nil at package scope
+	Var       *ast.Ident // Package level variable initialized by the construct, if any
+	Msg       ast.Expr   // Expression the message was taken from
+
+	// HasFormatVerbs reports whether the message is a format string holding
+	// verbs, as written before normalization. Such messages can't be shared as
+	// a single error value since their arguments differ.
+	HasFormatVerbs bool
+
+	at   token.Pos // Where to report the occurrence with ReportAtLiteral
+	msg  string    // Normalized message, before merging similar ones
+	kind string    // Whether the construct returns an error, logs or panics
+}
+
+// reportPos returns where diagnostics for the occurrence are reported,
+// preferring the name of a sentinel error variable over its initializer
+// unless reporting at the message literal was asked for.
+func (info ErrorInfo) reportPos() token.Pos {
+	if info.at.IsValid() {
+		return info.at
+	}
+	if info.Var != nil {
+		return info.Var.Pos()
+	}
+	return info.Pos.Pos()
+}
+
+// groupKey identifies a set of occurrences checked for duplicates
+type groupKey struct {
+	msg   string   // Normalized message
+	scope ast.Node // Enclosing file or function with -scope=file or function, nil otherwise
+	kind  string   // Kind of construct with -separate-by-kind, empty otherwise
+
+	// cluster numbers the groups a message is split into by -dedupe-window
+	cluster int
+}
+
+func run(pass *analysis.Pass, opts *Options) (interface{}, error) {
+	result, err := analyze(pass, opts)
+	if err != nil {
+		return nil, err
+	}
+
+	// Messages are shared with importers, and checked against imports
+	if opts.CrossPackage {
+		exportMessages(pass, result.keys, result.errorMap)
+		reportImported(pass, opts, result.keys, result.errorMap)
+	}
+
+	// Check for duplicates
+	diags, findings := result.diagnose(opts)
+	for _, diag := range diags {
+		pass.Report(diag)
+	}
+	result.findings = findings
+
+	// Roll the duplicates up into one line for the whole package
+	if opts.Summary {
+		if diag, ok := summarize(pass, findings); ok {
+			pass.Report(diag)
+		}
+	}
+	return result, nil
 }
 
-func run(pass *analysis.Pass) (interface{}, error) {
+// analyze extracts every message in the package and groups them, leaving
+// what's reported to the caller.
+func analyze(pass *analysis.Pass, opts *Options) (*Result, error) {
+	if err := opts.validate(); err != nil {
+		return nil, err
+	}
+	opts = opts.withPipeline()
+	ignored, err := opts.ignored()
+	if err != nil {
+		return nil, err
+	}
+
 	// Map to store error messages and their locations
-	errorMap := make(map[string][]ErrorInfo)
+	errorMap := make(map[groupKey][]ErrorInfo)
 
 	// Get the inspector from the analyzer requirements
 	inspector := pass.ResultOf[inspect.Analyzer].(*inspector.Inspector)
@@ -37,105 +132,398 @@ func run(pass *analysis.Pass) (interface{}, error) {
 		(*ast.CallExpr)(nil),
 	}
 
-	// Use Preorder to visit all call expressions
-	inspector.Preorder(nodeFilter, func(node ast.Node) {
+	// Lines annotated with //nolint:duperror are left out of every group
+	suppressed := findSuppressions(pass)
+
+	// Generated code is left alone unless asked for
+	var generated map[string]bool
+	if !opts.IncludeGenerated {
+		generated = findGenerated(pass)
+	}
+
+	// Files such as vendored dependencies are left out entirely
+	excluded := findExcluded(pass, opts.ExcludePaths)
+	if !opts.IncludeStringer {
+		for filename := range findStringer(pass) {
+			excluded[filename] = true
+		}
+	}
+
+	// Messages that are deliberately reused
+	allowed := opts.allowed()
+
+	// Messages known to mean the same thing
+	canonical := opts.canonical()
+
+	// Visit all call expressions, keeping the stack to find enclosing functions
+	var candidates []candidate
+	inspector.WithStack(nodeFilter, func(node ast.Node, push bool, stack []ast.Node) bool {
+		if !push {
+			return true
+		}
 		call := node.(*ast.CallExpr)
+		if !opts.IncludeTests && isTestFile(pass, call) {
+			return true
+		}
+		if filename := pass.Fset.Position(call.Pos()).Filename; generated[filename] || excluded[filename] {
+			return true
+		}
+		if opts.PublicAPIOnly && !returnedFromExported(stack) {
+			return true
+		}
+		sentinel := sentinelVar(stack)
+		if opts.ExportedOnly && !exportedFacing(stack, sentinel) {
+			return true
+		}
+		if opts.IgnoreTestHelpers {
+			if fn := enclosingDecl(stack); fn != nil && opts.isHelper(fn.Name.Name) {
+				return true
+			}
+		}
+
+		candidates = append(candidates, candidate{
+			call:     call,
+			file:     stack[0].(*ast.File),
+			fn:       enclosingFunc(stack),
+			sentinel: sentinel,
+		})
+		return true
+	})
 
-		// Check if this is a function call we're interested in
-		construct, msg := extractErrorMessage(call)
+	// Messages are extracted concurrently, then filtered and grouped in
+	// source order so the outcome matches a sequential pass
+	messages := extractAll(pass, opts, findLocals(pass), candidates)
+	for i, c := range candidates {
+		construct, text, msg, kind := messages[i].construct, messages[i].text, messages[i].msg, messages[i].kind
 		if construct == "" || msg == "" {
-			return
+			continue
+		}
+		msg = opts.Normalize(msg)
+
+		pos := pass.Fset.Position(c.call.Pos())
+		if !opts.IncludeTextless && !hasText(msg) {
+			opts.debugf("%s: skipping %q without any text", pos, text)
+			continue
+		}
+		if messageLength(msg) < opts.MinLength {
+			opts.debugf("%s: skipping %q shorter than %d runes", pos, text, opts.MinLength)
+			continue
+		}
+		if allowed[msg] {
+			opts.debugf("%s: skipping allowlisted %q", pos, text)
+			continue
+		}
+		if ignored != nil && ignored.MatchString(displayVerbs(msg)) {
+			opts.debugf("%s: skipping %q matching the ignore pattern", pos, text)
+			continue
 		}
+		if suppressed.suppressed(pos.Filename, pos.Line) {
+			opts.debugf("%s: skipping %q suppressed by nolint", pos, text)
+			continue
+		}
+		opts.debugf("%s: found %q from %s", pos, text, construct)
 
 		// Add to our map
 		info := ErrorInfo{
-			Pos:       node,
+			Pos:       c.call,
 			Construct: construct,
+			Text:      text,
+			Func:      c.fn,
+			Var:       c.sentinel,
+			Msg:       messages[i].arg,
+
+			HasFormatVerbs: messages[i].verbs,
+
+			kind: kind,
+		}
+		if opts.ReportAtLiteral {
+			info.at = info.Msg.Pos()
+		}
+
+		if c, ok := canonical[msg]; ok {
+			msg = c
+		}
+		info.msg = msg
+		key := groupKey{msg: msg}
+		switch opts.Scope {
+		case ScopeFile:
+			key.scope = c.file
+		case ScopeFunction:
+			key.scope = info.Func
+		}
+		if opts.SeparateByKind || kind == kindExpect {
+			key.kind = kind
 		}
+		errorMap[key] = append(errorMap[key], info)
+	}
 
-		errorMap[msg] = append(errorMap[msg], info)
+	// Sort messages, and the locations of each, so diagnostics are reported in
+	// a stable order and the first occurrence is always the earliest position
+	keys := make([]groupKey, 0, len(errorMap))
+	for key, locations := range errorMap {
+		keys = append(keys, key)
+		sort.Slice(locations, func(i, j int) bool {
+			return posLess(pass.Fset, locations[i].Pos.Pos(), locations[j].Pos.Pos())
+		})
+		if opts.CollapseConsecutive {
+			errorMap[key] = collapseConsecutive(pass.Fset, locations)
+		}
+	}
+	sort.Slice(keys, func(i, j int) bool {
+		if keys[i].msg != keys[j].msg {
+			return keys[i].msg < keys[j].msg
+		}
+		return posLess(pass.Fset, errorMap[keys[i]][0].Pos.Pos(), errorMap[keys[j]][0].Pos.Pos())
 	})
 
-	// Check for duplicates
-	for msg, locations := range errorMap {
-		if len(locations) > 1 {
-			// Report the first occurrence
-			firstLoc := locations[0]
-			pass.Reportf(firstLoc.Pos.Pos(), "duplicate error message %q used in multiple locations", msg)
+	// Near-duplicates are folded into a single group when asked for
+	var spellings map[groupKey][]string
+	if opts.Similarity > 0 {
+		if limit := opts.MaxFuzzyMessages; limit > 0 && len(keys) > limit {
+			opts.warnf("%s: skipping -similarity, %d distinct messages exceed -max-fuzzy-messages=%d", pass.Pkg.Path(), len(keys), limit)
+		} else {
+			keys, spellings = mergeSimilar(pass.Fset, keys, errorMap, opts.Similarity)
+		}
+	}
 
-			// Report all subsequent occurrences with reference to the first
-			for i := 1; i < len(locations); i++ {
-				pass.Reportf(locations[i].Pos.Pos(), "duplicate error message %q also used at %v",
-					msg, pass.Fset.Position(firstLoc.Pos.Pos()))
-			}
+	// Occurrences far apart from each other are grouped separately
+	if opts.DedupeWindow > 0 {
+		if spellings == nil {
+			spellings = make(map[groupKey][]string)
 		}
+		keys = splitByWindow(pass.Fset, keys, errorMap, spellings, opts.DedupeWindow)
 	}
 
-	return nil, nil
+	return newResult(pass, opts, keys, errorMap, spellings), nil
 }
 
-func extractErrorMessage(call *ast.CallExpr) (string, string) {
-	construct := getErrorConstructName(call)
-	if construct == "" {
-		return "", ""
+// posLess orders positions by file name and then offset. Files can be added
+// to a FileSet in any order, so comparing token.Pos values across files
+// wouldn't be stable between runs.
+func posLess(fset *token.FileSet, a, b token.Pos) bool {
+	pa, pb := fset.Position(a), fset.Position(b)
+	if pa.Filename != pb.Filename {
+		return pa.Filename < pb.Filename
 	}
+	return pa.Offset < pb.Offset
+}
 
-	var msgArg ast.Expr
+// fileCount returns the number of distinct files locations are in
+func fileCount(fset *token.FileSet, locations []ErrorInfo) int {
+	positions := make([]token.Position, len(locations))
+	for i, loc := range locations {
+		positions[i] = fset.Position(loc.Pos.Pos())
+	}
+	return distinctFiles(positions)
+}
 
-	// Check if there are any arguments
-	if len(call.Args) == 0 {
-		return "", ""
+// distinctFiles returns the number of distinct files positions are in
+func distinctFiles(positions []token.Position) int {
+	files := make(map[string]bool)
+	for _, pos := range positions {
+		files[pos.Filename] = true
+	}
+	return len(files)
+}
+
+// extractErrorMessage returns the construct used by call along with its
+// message as written, the message normalized for comparison and the
+// expression it was taken from.
+func extractErrorMessage(pass *analysis.Pass, opts *Options, locals localValues, call *ast.CallExpr) extracted {
+	construct := getErrorConstruct(pass, opts, call)
+	if construct.Name == "" {
+		return extracted{}
+	}
+	if opts.OnlyFormatStrings && !construct.IsFormat {
+		return extracted{}
 	}
 
-	switch construct {
-	case "errors.New":
+	// Each case checks that the argument it reads exists, as calls with too
+	// few arguments, like a chained Logf() on a variadic logger, still parse
+	var msgArg ast.Expr
+	switch {
+	case construct.Msg != nil:
+		// A detector already located the message
+		msgArg = construct.Msg
+
+	case construct.Name == "errors.New":
 		// errors.New takes a single string argument
 		if len(call.Args) != 1 {
-			return "", ""
+			return extracted{}
 		}
 		msgArg = call.Args[0]
 
-	case "fmt.Errorf":
-		// fmt.Errorf takes a format string and optional arguments
-		msgArg = call.Args[0]
-
-	case "log", "logger", "Log", "Logf", "LogError", "LogErrorf":
-		// Log functions take format string as first argument
-		msgArg = call.Args[0]
+	case construct.MsgIndex >= 0:
+		// The construct knows exactly which argument holds the message
+		if construct.MsgIndex >= len(call.Args) {
+			return extracted{}
+		}
+		msgArg = call.Args[construct.MsgIndex]
 
 	default:
+		if len(call.Args) == 0 {
+			return extracted{}
+		}
+
 		// For custom error constructors that likely take a message as first arg
 		// First, check if the first argument is a string
-		if len(call.Args) > 0 {
-			if lit, ok := call.Args[0].(*ast.BasicLit); ok && lit.Kind == token.STRING {
-				msgArg = lit
-			} else {
-				// If first arg isn't a string, try to find any string literal among arguments
-				for _, arg := range call.Args {
-					if lit, ok := arg.(*ast.BasicLit); ok && lit.Kind == token.STRING {
-						msgArg = lit
-						break
-					}
+		if lit, ok := ast.Unparen(call.Args[0]).(*ast.BasicLit); ok && lit.Kind == token.STRING {
+			msgArg = lit
+		} else if !isString(pass, call.Args[0]) {
+			// If first arg isn't a string, try to find any string literal among arguments.
+			// A dynamic string, like one built with strings.Builder, is the message
+			// itself so a later literal would only be a stray field name or code.
+			for _, arg := range call.Args {
+				if lit, ok := ast.Unparen(arg).(*ast.BasicLit); ok && lit.Kind == token.STRING {
+					msgArg = lit
+					break
 				}
 			}
 		}
 
 		if msgArg == nil {
-			return "", ""
+			return extracted{}
+		}
+	}
+
+	// A message held in a local variable assigned once, as in
+	// msg := fmt.Sprintf(...); errors.New(msg), is taken from its value
+	msgArg = locals.resolve(pass, msgArg)
+
+	// A message built with fmt.Sprintf, as in errors.New(fmt.Sprintf(...)),
+	// is its format string
+	isFormat := construct.IsFormat
+	if format, ok := sprintfFormat(pass, msgArg); ok {
+		msgArg, isFormat = format, true
+	}
+
+	text := extractStringLiteral(pass, opts, msgArg)
+	if text == "" {
+		return extracted{}
+	}
+
+	msg := text
+	if isFormat {
+		if opts.NormalizeQuoteVerbs {
+			msg = normalizeQuoteVerbs(msg)
 		}
+		msg = normalizeVerbs(msg)
+	}
+	kind := construct.Kind
+	if kind == "" {
+		kind = constructKind(construct.Name)
 	}
+	return extracted{construct: construct.Name, kind: kind, text: text, msg: msg, arg: msgArg, verbs: isFormat && hasVerbs(text)}
+}
 
-	msg := extractStringLiteral(msgArg)
-	if msg == "" {
-		return "", ""
+// sprintfFormat returns the format string of a fmt.Sprintf call
+func sprintfFormat(pass *analysis.Pass, expr ast.Expr) (ast.Expr, bool) {
+	call, ok := ast.Unparen(expr).(*ast.CallExpr)
+	if !ok || len(call.Args) == 0 || !isFunc(pass, call.Fun, "fmt", "Sprintf") {
+		return nil, false
 	}
+	return call.Args[0], true
+}
 
-	return construct, msg
+// construct describes a recognized error construction call and where its message lives.
+type construct struct {
+	Name     string   // Which error construction method was used
+	MsgIndex int      // Argument holding the message, or -1 to search for a string literal
+	IsFormat bool     // Whether the message is a printf-style format string
+	Msg      ast.Expr // Message found by a ConstructDetector, overriding MsgIndex
+	Kind     string   // Kind of construct, derived from Name when empty
 }
 
-func getErrorConstructName(call *ast.CallExpr) string {
+func getErrorConstruct(pass *analysis.Pass, opts *Options, call *ast.CallExpr) construct {
+	// Cancellation functions receive an error, they never construct one
+	if isCancelFunc(pass, call.Fun) {
+		return construct{}
+	}
+
+	// err.Error() reads the message of an existing error, and would otherwise
+	// match the heuristics for names ending in Error
+	if sel, ok := call.Fun.(*ast.SelectorExpr); ok && sel.Sel.Name == "Error" && len(call.Args) == 0 {
+		return construct{}
+	}
+
+	// Constructors registered by the user take precedence over the heuristics,
+	// with fully qualified names preferred to bare ones. They construct errors
+	// whatever their import path holds, so their kind isn't guessed from it.
+	for _, name := range []string{qualifiedName(pass, call.Fun), calleeName(call.Fun)} {
+		if name == "" {
+			continue
+		}
+		if idx, ok := opts.Constructors[name]; ok {
+			return construct{
+				Name:     name,
+				MsgIndex: idx,
+				IsFormat: isFormatName(name),
+				Kind:     kindError,
+			}
+		}
+	}
+
+	// http.Error(w, msg, code) writes msg as the response body
+	if opts.IncludeHTTP && isFunc(pass, call.Fun, "net/http", "Error") {
+		return construct{Name: "http.Error", MsgIndex: 1}
+	}
+
 	// First, handle chained calls like logger.Info().Logf()
 	if selExpr, ok := call.Fun.(*ast.SelectorExpr); ok {
+		// Test assertions like t.Fatalf("expected 200 got %d", code), only
+		// when tests are checked at all
+		if opts.IncludeTests && isTestingTB(pass, selExpr.X) {
+			switch selExpr.Sel.Name {
+			case "Errorf", "Fatalf", "Logf", "Skipf":
+				return construct{Name: "t." + selExpr.Sel.Name, IsFormat: true}
+			}
+		}
+
+		// testify's ErrorContains takes the substring an error is expected to
+		// hold, from the package level functions and the Assertions methods
+		if opts.IncludeTestify && selExpr.Sel.Name == "ErrorContains" {
+			for _, pkg := range []string{"assert", "require"} {
+				path := "github.com/stretchr/testify/" + pkg
+				if isFunc(pass, selExpr, path, "ErrorContains") {
+					return construct{Name: pkg + ".ErrorContains", MsgIndex: 2}
+				}
+				if isMethodOn(pass, selExpr, path, "Assertions") {
+					return construct{Name: pkg + ".ErrorContains", MsgIndex: 1}
+				}
+			}
+		}
+
+		// Methods on *cobra.Command print errors for CLI tools
+		if isMethodOn(pass, selExpr, "github.com/spf13/cobra", "Command") {
+			switch selExpr.Sel.Name {
+			case "PrintErr", "PrintErrf", "PrintErrln":
+				return construct{
+					Name:     "cobra." + selExpr.Sel.Name,
+					IsFormat: selExpr.Sel.Name == "PrintErrf",
+				}
+			}
+		}
+
+		// Methods on moov's log.Logger, whatever the variable holding it is
+		// called, as with the logger returned by log.NewBufferLogger()
+		if isMethodOn(pass, selExpr, "github.com/moov-io/base/log", "Logger") {
+			switch selExpr.Sel.Name {
+			case "Log", "Logf", "LogError", "LogErrorf":
+				return construct{
+					Name:     selExpr.Sel.Name,
+					IsFormat: isFormatName(selExpr.Sel.Name),
+				}
+			}
+		}
+
+		// log/slog takes a plain message followed by key-value pairs, both from
+		// the package level functions and methods on *slog.Logger
+		if isSlogFunc(pass, selExpr) {
+			if idx, ok := slogMessageIndex(selExpr.Sel.Name); ok {
+				return construct{Name: "slog." + selExpr.Sel.Name, MsgIndex: idx}
+			}
+		}
+
 		// Check if the selector's X is another call expression (method chaining)
 		if _, ok := selExpr.X.(*ast.CallExpr); ok {
 			// This handles chained methods like logger.Info().Logf()
@@ -144,48 +532,75 @@ func getErrorConstructName(call *ast.CallExpr) string {
 				selExpr.Sel.Name == "LogErrorf" ||
 				selExpr.Sel.Name == "LogError" ||
 				selExpr.Sel.Name == "Log" {
-				return selExpr.Sel.Name
+				return construct{
+					Name:     selExpr.Sel.Name,
+					IsFormat: isFormatName(selExpr.Sel.Name),
+				}
 			}
 		}
 
 		// Check for standard selector expressions (e.g., errors.New, fmt.Errorf)
 		if pkgIdent, ok := selExpr.X.(*ast.Ident); ok {
 			// Common error construction patterns
-			if pkgIdent.Name == "errors" && selExpr.Sel.Name == "New" {
-				return "errors.New"
+			if pkgIdent.Name == "errors" {
+				// Covers the standard library along with github.com/pkg/errors
+				switch selExpr.Sel.Name {
+				case "New":
+					return construct{Name: "errors.New"}
+				case "Errorf":
+					return construct{Name: "errors.Errorf", IsFormat: true}
+				case "Wrap":
+					return construct{Name: "errors.Wrap", MsgIndex: 1}
+				case "Wrapf":
+					return construct{Name: "errors.Wrapf", MsgIndex: 1, IsFormat: true}
+				}
 			}
 			if pkgIdent.Name == "fmt" && selExpr.Sel.Name == "Errorf" {
-				return "fmt.Errorf"
-			}
-
-			// Check for logging functions
-			if pkgIdent.Name == "log" || strings.Contains(strings.ToLower(pkgIdent.Name), "log") {
-				logFuncSuffixes := []string{
-					"", "f", "ln", // Log, Logf, Logln
-					"Error", "Errorf", "Errorln",
-					"Fatal", "Fatalf", "Fatalln",
-					"Panic", "Panicf", "Panicln",
-					"Warning", "Warningf", "Warningln",
-					"Info", "Infof", "Infoln",
-				}
+				return construct{Name: "fmt.Errorf", IsFormat: true}
+			}
 
-				for _, suffix := range logFuncSuffixes {
-					if selExpr.Sel.Name == suffix ||
-						selExpr.Sel.Name == "Log"+suffix ||
-						selExpr.Sel.Name == "Print"+suffix {
-						return pkgIdent.Name
+			// gRPC's status.Error(code, msg) and status.Errorf(code, format, ...)
+			if pkgIdent.Name == "status" {
+				switch selExpr.Sel.Name {
+				case "Error", "Errorf":
+					return construct{
+						Name:     "status." + selExpr.Sel.Name,
+						MsgIndex: 1,
+						IsFormat: selExpr.Sel.Name == "Errorf",
 					}
 				}
 			}
 
-			// Check for common error constructor patterns
-			if strings.HasSuffix(selExpr.Sel.Name, "Error") ||
-				strings.HasPrefix(selExpr.Sel.Name, "New") ||
-				strings.Contains(selExpr.Sel.Name, "Error") ||
-				strings.Contains(strings.ToLower(selExpr.Sel.Name), "fail") {
-				return selExpr.Sel.Name
+			if c, ok := receiverConstruct(pkgIdent.Name, selExpr.Sel.Name); ok {
+				return c
+			}
+		}
+
+		// Fields and packages reached through another selector, as in
+		// s.logger.Errorf or a.b.Errorf, are matched on the field's name like
+		// identifiers are, falling back to the name of its type so a field
+		// such as out *log.Logger still counts as a logger
+		if inner, ok := selExpr.X.(*ast.SelectorExpr); ok {
+			recv := inner.Sel.Name
+			if name := namedTypeName(pass, inner); !isLoggerName(recv) && isLoggerName(name) {
+				recv = name
+			}
+			if c, ok := receiverConstruct(recv, selExpr.Sel.Name); ok {
+				return c
 			}
 		}
+
+		// Any other value can build errors too, as in handlers[0].NewError
+		// or newService().Fail, though without a name it isn't a logger
+		if c, ok := builderConstruct(selExpr.Sel.Name); ok {
+			return c
+		}
+	}
+
+	// panic("msg") with a string message. Panicking with an error, as in
+	// panic(fmt.Errorf(...)), leaves the message to the inner constructor.
+	if isBuiltin(pass, call.Fun, "panic") && len(call.Args) == 1 && isString(pass, call.Args[0]) {
+		return construct{Name: "panic"}
 	}
 
 	// Also check for direct function idents (not selector expressions)
@@ -195,27 +610,457 @@ func getErrorConstructName(call *ast.CallExpr) string {
 			(strings.Contains(ident.Name, "Error") ||
 				strings.Contains(ident.Name, "Err") ||
 				strings.Contains(ident.Name, "Fail")) {
-			return ident.Name
+			return construct{
+				Name:     ident.Name,
+				MsgIndex: -1,
+				IsFormat: isFormatName(ident.Name),
+			}
+		}
+	}
+
+	// Finally, ask the detectors registered by the embedder
+	for _, detector := range opts.Detectors {
+		if name, msg, isFormat, ok := detector.Detect(call, pass); ok && msg != nil {
+			return construct{Name: name, IsFormat: isFormat, Msg: msg}
+		}
+	}
+
+	return construct{}
+}
+
+// calleeName returns the bare name of a called function or method
+func calleeName(fun ast.Expr) string {
+	switch f := fun.(type) {
+	case *ast.Ident:
+		return f.Name
+	case *ast.SelectorExpr:
+		return f.Sel.Name
+	}
+	return ""
+}
+
+// qualifiedName returns the called function as "import/path.Func", or
+// "import/path.Type.Method" for methods, when it can be resolved
+func qualifiedName(pass *analysis.Pass, fun ast.Expr) string {
+	if pass.TypesInfo == nil {
+		return ""
+	}
+	var ident *ast.Ident
+	switch f := fun.(type) {
+	case *ast.Ident:
+		ident = f
+	case *ast.SelectorExpr:
+		ident = f.Sel
+	default:
+		return ""
+	}
+	fn, ok := pass.TypesInfo.Uses[ident].(*types.Func)
+	if !ok || fn.Pkg() == nil {
+		return ""
+	}
+	recv := fn.Type().(*types.Signature).Recv()
+	if recv == nil {
+		return fn.Pkg().Path() + "." + fn.Name()
+	}
+	t := recv.Type()
+	if ptr, ok := t.(*types.Pointer); ok {
+		t = ptr.Elem()
+	}
+	named, ok := t.(*types.Named)
+	if !ok {
+		return ""
+	}
+	return fn.Pkg().Path() + "." + named.Obj().Name() + "." + fn.Name()
+}
+
+// isTestFile reports whether node is in a _test.go file
+func isTestFile(pass *analysis.Pass, node ast.Node) bool {
+	return strings.HasSuffix(pass.Fset.Position(node.Pos()).Filename, "_test.go")
+}
+
+// Kinds of constructs kept apart by SeparateByKind. Expected error substrings
+// are always kept apart, since they're meant to match a message.
+const (
+	kindError  = "error"
+	kindLog    = "log"
+	kindPanic  = "panic"
+	kindExpect = "expect"
+)
+
+// constructKind classifies a construct as returning an error, logging or
+// printing a message, or panicking. Constructs are errors unless their name
+// says otherwise, as with log.Printf, glog, Logf, cobra's PrintErr or t.Fatalf.
+// Only the name after the import path counts, so a detector's
+// example.com/catalog/errs.New is still an error.
+func constructKind(construct string) string {
+	construct = construct[strings.LastIndex(construct, "/")+1:]
+	switch {
+	case construct == "panic":
+		return kindPanic
+	case strings.HasSuffix(construct, ".ErrorContains"):
+		return kindExpect
+	case strings.Contains(strings.ToLower(construct), "log"),
+		strings.HasPrefix(construct, "cobra."),
+		strings.HasPrefix(construct, "t."):
+		return kindLog
+	}
+	return kindError
+}
+
+// isSlogFunc reports whether sel refers to a log/slog function or a method
+// on *slog.Logger
+func isSlogFunc(pass *analysis.Pass, sel *ast.SelectorExpr) bool {
+	return isFunc(pass, sel, "log/slog", sel.Sel.Name) || isMethodOn(pass, sel, "log/slog", "Logger")
+}
+
+// slogMessageIndex returns the index of the message argument for the slog
+// function or method name, e.g. 1 for InfoContext(ctx, msg, args...).
+func slogMessageIndex(name string) (int, bool) {
+	switch name {
+	case "Debug", "Info", "Warn", "Error":
+		return 0, true
+	case "DebugContext", "InfoContext", "WarnContext", "ErrorContext":
+		return 1, true
+	case "Log", "LogAttrs":
+		return 2, true
+	}
+	return 0, false
+}
+
+// logFuncSuffixes complete the names of logging methods after Log or Print,
+// or on their own, as in Logf, Println or Errorf
+var logFuncSuffixes = []string{
+	"", "f", "ln", // Log, Logf, Logln
+	"Error", "Errorf", "Errorln",
+	"Fatal", "Fatalf", "Fatalln",
+	"Exit", "Exitf", "Exitln", // glog
+	"Panic", "Panicf", "Panicln",
+	"Warning", "Warningf", "Warningln",
+	"Info", "Infof", "Infoln",
+}
+
+// receiverConstruct matches the call of method on recv, the name of a
+// package, variable or field, against the naming patterns of loggers and
+// error builders.
+func receiverConstruct(recv, method string) (construct, bool) {
+	// Check for logging functions
+	if isLoggerName(recv) {
+		for _, suffix := range logFuncSuffixes {
+			if method == suffix || method == "Log"+suffix || method == "Print"+suffix {
+				return construct{
+					Name:     recv,
+					IsFormat: isFormatName(method),
+				}, true
+			}
 		}
 	}
 
+	return builderConstruct(method)
+}
+
+// builderConstruct matches a method named like it builds an error, as in
+// NewError, Errorf or failWith, whatever it's called on
+func builderConstruct(method string) (construct, bool) {
+	if strings.HasSuffix(method, "Error") ||
+		strings.HasPrefix(method, "New") ||
+		strings.Contains(method, "Error") ||
+		strings.Contains(strings.ToLower(method), "fail") {
+		return construct{
+			Name:     method,
+			MsgIndex: -1,
+			IsFormat: isFormatName(method),
+		}, true
+	}
+	return construct{}, false
+}
+
+// isLoggerName reports whether name looks like a logger, as in log, logger or
+// auditLog
+func isLoggerName(name string) bool {
+	return strings.Contains(strings.ToLower(name), "log")
+}
+
+// namedTypeName returns the name of the named type of expr, looking through
+// a pointer, or an empty string when it has none
+func namedTypeName(pass *analysis.Pass, expr ast.Expr) string {
+	if pass.TypesInfo == nil {
+		return ""
+	}
+	t := pass.TypesInfo.TypeOf(expr)
+	if ptr, ok := t.(*types.Pointer); ok {
+		t = ptr.Elem()
+	}
+	if named, ok := t.(*types.Named); ok {
+		return named.Obj().Name()
+	}
 	return ""
 }
 
-func extractStringLiteral(expr ast.Expr) string {
+// isFormatName reports whether a function name follows the printf convention
+// of ending in "f", e.g. Errorf or Logf.
+func isFormatName(name string) bool {
+	return strings.HasSuffix(name, "f")
+}
+
+// statusText evaluates http.StatusText(code) when code is a constant
+func statusText(pass *analysis.Pass, call *ast.CallExpr) (string, bool) {
+	if !isFunc(pass, call.Fun, "net/http", "StatusText") || len(call.Args) != 1 {
+		return "", false
+	}
+	tv, ok := pass.TypesInfo.Types[call.Args[0]]
+	if !ok || tv.Value == nil || tv.Value.Kind() != constant.Int {
+		return "", false
+	}
+	code, ok := constant.Int64Val(tv.Value)
+	if !ok {
+		return "", false
+	}
+	text := http.StatusText(int(code))
+	return text, text != ""
+}
+
+// isFunc reports whether fun refers to the package level function pkgPath.name
+func isFunc(pass *analysis.Pass, fun ast.Expr, pkgPath, name string) bool {
+	if pass.TypesInfo == nil {
+		return false
+	}
+	var ident *ast.Ident
+	switch f := fun.(type) {
+	case *ast.Ident:
+		ident = f
+	case *ast.SelectorExpr:
+		ident = f.Sel
+	default:
+		return false
+	}
+	fn, ok := pass.TypesInfo.Uses[ident].(*types.Func)
+	if !ok || fn.Pkg() == nil || fn.Type().(*types.Signature).Recv() != nil {
+		return false
+	}
+	return fn.Pkg().Path() == pkgPath && fn.Name() == name
+}
+
+// isCancelFunc reports whether fun is a context.CancelCauseFunc or
+// context.CancelFunc value, such as the cancel returned by context.WithCancelCause.
+func isCancelFunc(pass *analysis.Pass, fun ast.Expr) bool {
+	if pass.TypesInfo == nil {
+		return false
+	}
+	named, ok := pass.TypesInfo.TypeOf(fun).(*types.Named)
+	if !ok || named.Obj().Pkg() == nil || named.Obj().Pkg().Path() != "context" {
+		return false
+	}
+	return named.Obj().Name() == "CancelCauseFunc" || named.Obj().Name() == "CancelFunc"
+}
+
+// isMethodOn reports whether sel refers to a method declared on the named type
+// pkgPath.typeName or a pointer to it.
+func isMethodOn(pass *analysis.Pass, sel *ast.SelectorExpr, pkgPath, typeName string) bool {
+	if pass.TypesInfo == nil {
+		return false
+	}
+	fn, ok := pass.TypesInfo.Uses[sel.Sel].(*types.Func)
+	if !ok {
+		return false
+	}
+	recv := fn.Type().(*types.Signature).Recv()
+	if recv == nil {
+		return false
+	}
+	t := recv.Type()
+	if ptr, ok := t.(*types.Pointer); ok {
+		t = ptr.Elem()
+	}
+	named, ok := t.(*types.Named)
+	if !ok || named.Obj().Pkg() == nil {
+		return false
+	}
+	return named.Obj().Pkg().Path() == pkgPath && named.Obj().Name() == typeName
+}
+
+func extractStringLiteral(pass *analysis.Pass, opts *Options, expr ast.Expr) string {
+	var raw string
+
+	// Parentheses around the message, as in errors.New(("msg")), are dropped
+	switch e := ast.Unparen(expr).(type) {
+	case *ast.BasicLit:
+		if e.Kind != token.STRING {
+			return ""
+		}
+		value, ok := literalText(e)
+		if !ok {
+			return ""
+		}
+		raw = value
+
+	case *ast.BinaryExpr:
+		if e.Op != token.ADD {
+			return ""
+		}
+		// Concatenated literals, often split across lines, are joined directly
+		if value, ok := concatLiterals(e); ok {
+			raw = value
+			break
+		}
+		// Concatenations like prefix + "suffix" are folded by the type checker,
+		// but only when every operand is a compile-time constant.
+		value, ok := constantString(pass, e)
+		if !ok {
+			return ""
+		}
+		raw = value
+
+	case *ast.Ident, *ast.SelectorExpr:
+		// Named constants, as in errors.New(msgTimeout) or pkg.MsgTimeout,
+		// are resolved to their value. Variables aren't known until runtime
+		// and are skipped.
+		value, ok := constantString(pass, e)
+		if !ok {
+			return ""
+		}
+		raw = value
+
+	case *ast.CallExpr:
+		// Conversions like string(formatConst) are folded when the type
+		// checker knows the resulting constant
+		if isConversion(pass, e) {
+			value, ok := constantString(pass, e)
+			if !ok {
+				return ""
+			}
+			raw = value
+			break
+		}
+
+		// http.StatusText(code) is folded to the status text for constant codes.
+		// Any other call, such as strconv.Itoa(code), is only known at runtime
+		// and the message is skipped as a whole.
+		if !opts.IncludeHTTP {
+			return ""
+		}
+		value, ok := statusText(pass, e)
+		if !ok {
+			return ""
+		}
+		raw = value
+
+	default:
+		return ""
+	}
+
+	return raw
+}
+
+// literalText returns the value of a string literal with its escapes
+// interpreted, so raw and interpreted literals of the same text are equal.
+func literalText(lit *ast.BasicLit) (string, bool) {
+	value, err := strconv.Unquote(lit.Value)
+	if err != nil {
+		return "", false
+	}
+	return value, true
+}
+
+// concatLiterals joins "a" + "b" + ... when every operand is a string literal
+func concatLiterals(expr ast.Expr) (string, bool) {
 	switch e := expr.(type) {
 	case *ast.BasicLit:
-		if e.Kind == token.STRING {
-			// Remove quotes and process format strings
-			raw := strings.Trim(e.Value, "`\"")
+		if e.Kind != token.STRING {
+			return "", false
+		}
+		return literalText(e)
+
+	case *ast.BinaryExpr:
+		if e.Op != token.ADD {
+			return "", false
+		}
+		left, ok := concatLiterals(e.X)
+		if !ok {
+			return "", false
+		}
+		right, ok := concatLiterals(e.Y)
+		if !ok {
+			return "", false
+		}
+		return left + right, true
 
-			// For format strings, we normalize format specifiers
-			// This approach catches %s, %d, %v, etc.
-			formatSpecifier := regexp.MustCompile(`%[a-zA-Z0-9\.\-\+#]*[a-zA-Z]`)
-			normalized := formatSpecifier.ReplaceAllString(raw, "%x")
+	case *ast.ParenExpr:
+		return concatLiterals(e.X)
+	}
+	return "", false
+}
 
-			return normalized
+// isTestingTB reports whether x is a *testing.T, *testing.B, *testing.F or
+// any other implementation of testing.TB. Without type information, an
+// identifier named t is assumed to be one.
+func isTestingTB(pass *analysis.Pass, x ast.Expr) bool {
+	if pass.TypesInfo == nil {
+		ident, ok := x.(*ast.Ident)
+		return ok && ident.Name == "t"
+	}
+	t := pass.TypesInfo.TypeOf(x)
+	if t == nil {
+		return false
+	}
+	for _, imp := range pass.Pkg.Imports() {
+		if imp.Path() != "testing" {
+			continue
+		}
+		tb, ok := imp.Scope().Lookup("TB").(*types.TypeName)
+		if !ok {
+			return false
 		}
+		iface, ok := tb.Type().Underlying().(*types.Interface)
+		return ok && types.Implements(t, iface)
 	}
-	return ""
+	return false
+}
+
+// isBuiltin reports whether fun refers to the predeclared function name
+func isBuiltin(pass *analysis.Pass, fun ast.Expr, name string) bool {
+	if pass.TypesInfo == nil {
+		return false
+	}
+	ident, ok := ast.Unparen(fun).(*ast.Ident)
+	if !ok || ident.Name != name {
+		return false
+	}
+	_, ok = pass.TypesInfo.Uses[ident].(*types.Builtin)
+	return ok
+}
+
+// isString reports whether expr has a string type
+func isString(pass *analysis.Pass, expr ast.Expr) bool {
+	if pass.TypesInfo == nil {
+		return false
+	}
+	t := pass.TypesInfo.TypeOf(expr)
+	if t == nil {
+		return false
+	}
+	basic, ok := t.Underlying().(*types.Basic)
+	return ok && basic.Info()&types.IsString != 0
+}
+
+// isConversion reports whether call converts its argument to another type,
+// as in string(b), rather than calling a function
+func isConversion(pass *analysis.Pass, call *ast.CallExpr) bool {
+	if pass.TypesInfo == nil {
+		return false
+	}
+	tv, ok := pass.TypesInfo.Types[call.Fun]
+	return ok && tv.IsType()
+}
+
+// constantString returns the value of expr when the type checker was able to
+// evaluate it to a constant string.
+func constantString(pass *analysis.Pass, expr ast.Expr) (string, bool) {
+	if pass.TypesInfo == nil {
+		return "", false
+	}
+	tv, ok := pass.TypesInfo.Types[expr]
+	if !ok || tv.Value == nil || tv.Value.Kind() != constant.String {
+		return "", false
+	}
+	return constant.StringVal(tv.Value), true
 }
@@ -1,10 +1,23 @@
 package duperrormsg_test
 
 import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"go/ast"
+	"go/token"
+	"io"
+	"log"
+	"os"
 	"path/filepath"
+	"reflect"
+	"strings"
 	"testing"
 
+	"golang.org/x/tools/go/analysis"
 	"golang.org/x/tools/go/analysis/analysistest"
+	"golang.org/x/tools/go/analysis/checker"
+	"golang.org/x/tools/go/packages"
 
 	"github.com/adamdecaf/duperrormsg/duperrormsg"
 )
@@ -14,5 +27,1234 @@ func TestAll(t *testing.T) {
 	if err != nil {
 		t.Fatal(err)
 	}
-	analysistest.Run(t, wd, duperrormsg.Analyzer, "tests")
+	analysistest.Run(t, wd, duperrormsg.Analyzer, "tests", "pkgerrors", "grpcerrors", "glogerrors", "slogerrors")
+}
+
+// setFlag changes an analyzer flag for the duration of the test
+func setFlag(t *testing.T, name, value string) {
+	t.Helper()
+
+	f := duperrormsg.Analyzer.Flags.Lookup(name)
+	if f == nil {
+		t.Fatalf("unknown flag %q", name)
+	}
+	previous := f.Value.String()
+	if err := f.Value.Set(value); err != nil {
+		t.Fatal(err)
+	}
+	t.Cleanup(func() {
+		f.Value.Set(previous)
+	})
+}
+
+func TestGoroutineEnclosure(t *testing.T) {
+	wd, err := filepath.Abs("testdata")
+	if err != nil {
+		t.Fatal(err)
+	}
+	results := analysistest.Run(t, wd, duperrormsg.Analyzer, "enclosure")
+
+	funcs := make(map[string][]string)
+	for _, group := range results[0].Result.(*duperrormsg.Result).Groups {
+		for _, occ := range group.Occurrences {
+			funcs[group.Text] = append(funcs[group.Text], occ.Func)
+		}
+	}
+
+	// Closures launched by go statements are attributed to the outer function
+	if got := funcs["worker %s stalled"]; len(got) != 2 || got[0] != "startWorkers" || got[1] != "startWorkers" {
+		t.Errorf("unexpected goroutine attribution: %v", got)
+	}
+	// Other closures are their own function
+	if got := funcs["retry budget exhausted"]; len(got) != 1 || got[0] != "startWorkers.func3" {
+		t.Errorf("unexpected closure attribution: %v", got)
+	}
+}
+
+func TestNormalizePunct(t *testing.T) {
+	wd, err := filepath.Abs("testdata")
+	if err != nil {
+		t.Fatal(err)
+	}
+	setFlag(t, "normalize-punct", "true")
+	analysistest.Run(t, wd, duperrormsg.Analyzer, "punct")
+}
+
+func TestTransforms(t *testing.T) {
+	opts := &duperrormsg.Options{
+		NormalizePunct: true,
+		Transforms: []func(string) string{
+			strings.TrimSpace,
+			strings.ToLower,
+			func(msg string) string {
+				return strings.TrimPrefix(msg, "billing: ")
+			},
+		},
+	}
+
+	// Built-in stages run before the caller's transforms, which run in order
+	if got := opts.Normalize("  Billing: Retry – Later "); got != "retry - later" {
+		t.Errorf("unexpected key %q", got)
+	}
+	if got := (&duperrormsg.Options{}).Normalize("  Retry – Later "); got != "Retry – Later" {
+		t.Errorf("expected only whitespace changes by default, got %q", got)
+	}
+	if got := (&duperrormsg.Options{KeepWhitespace: true}).Normalize("  Retry – Later "); got != "  Retry – Later " {
+		t.Errorf("expected no changes without transforms, got %q", got)
+	}
+
+	wd, err := filepath.Abs("testdata")
+	if err != nil {
+		t.Fatal(err)
+	}
+	analysistest.Run(t, wd, duperrormsg.NewAnalyzer(*opts), "transforms")
+}
+
+func TestCrossPackage(t *testing.T) {
+	wd, err := filepath.Abs("testdata")
+	if err != nil {
+		t.Fatal(err)
+	}
+	// Facts are only declared when they're used, since drivers analyze every
+	// dependency of analyzers declaring them
+	if len(duperrormsg.Analyzer.FactTypes) > 0 {
+		t.Errorf("unexpected fact types %v without -cross-package", duperrormsg.Analyzer.FactTypes)
+	}
+
+	setFlag(t, "cross-package", "true")
+	analysistest.Run(t, wd, duperrormsg.Analyzer, "crosspkg/api")
+	if len(duperrormsg.Analyzer.FactTypes) == 0 {
+		t.Error("expected fact types with -cross-package")
+	}
+}
+
+func TestNormalizeWhitespace(t *testing.T) {
+	wd, err := filepath.Abs("testdata")
+	if err != nil {
+		t.Fatal(err)
+	}
+	analysistest.Run(t, wd, duperrormsg.Analyzer, "whitespace")
+
+	setFlag(t, "normalize-whitespace", "false")
+	analysistest.Run(t, wd, duperrormsg.Analyzer, "exactwhitespace")
+}
+
+func TestNormalizeEscapes(t *testing.T) {
+	wd, err := filepath.Abs("testdata")
+	if err != nil {
+		t.Fatal(err)
+	}
+	setFlag(t, "normalize-whitespace", "false")
+	setFlag(t, "normalize-escapes", "true")
+	analysistest.Run(t, wd, duperrormsg.Analyzer, "controlchars")
+}
+
+func TestIgnoreCase(t *testing.T) {
+	wd, err := filepath.Abs("testdata")
+	if err != nil {
+		t.Fatal(err)
+	}
+	analysistest.Run(t, wd, duperrormsg.Analyzer, "exactcase")
+
+	setFlag(t, "ignore-case", "true")
+	analysistest.Run(t, wd, duperrormsg.Analyzer, "ignorecase")
+}
+
+func TestIgnoreWordOrder(t *testing.T) {
+	wd, err := filepath.Abs("testdata")
+	if err != nil {
+		t.Fatal(err)
+	}
+	setFlag(t, "ignore-word-order", "true")
+	analysistest.Run(t, wd, duperrormsg.Analyzer, "wordorder")
+}
+
+func TestNormalizeQuoteVerbs(t *testing.T) {
+	wd, err := filepath.Abs("testdata")
+	if err != nil {
+		t.Fatal(err)
+	}
+	setFlag(t, "normalize-quote-verbs", "true")
+	analysistest.Run(t, wd, duperrormsg.Analyzer, "quoteverbs")
+}
+
+func TestEscapes(t *testing.T) {
+	wd, err := filepath.Abs("testdata")
+	if err != nil {
+		t.Fatal(err)
+	}
+	// Compare whitespace exactly so only decoding makes the tabs equal
+	setFlag(t, "normalize-whitespace", "false")
+	analysistest.Run(t, wd, duperrormsg.Analyzer, "escapes")
+}
+
+func TestIncludeHTTP(t *testing.T) {
+	wd, err := filepath.Abs("testdata")
+	if err != nil {
+		t.Fatal(err)
+	}
+	analyzer := duperrormsg.NewAnalyzer(duperrormsg.Options{
+		IncludeHTTP: true,
+	})
+	analysistest.Run(t, wd, analyzer, "httperrors")
+}
+
+func TestRequireText(t *testing.T) {
+	wd, err := filepath.Abs("testdata")
+	if err != nil {
+		t.Fatal(err)
+	}
+	analysistest.Run(t, wd, duperrormsg.Analyzer, "textless")
+
+	setFlag(t, "require-text", "false")
+	analysistest.Run(t, wd, duperrormsg.Analyzer, "includetextless")
+}
+
+func TestMinLength(t *testing.T) {
+	wd, err := filepath.Abs("testdata")
+	if err != nil {
+		t.Fatal(err)
+	}
+	setFlag(t, "min-length", "5")
+	analysistest.Run(t, wd, duperrormsg.Analyzer, "minlength")
+}
+
+func TestAllowlist(t *testing.T) {
+	wd, err := filepath.Abs("testdata")
+	if err != nil {
+		t.Fatal(err)
+	}
+	setFlag(t, "allowlist", "not implemented, user %s gone")
+	analysistest.Run(t, wd, duperrormsg.Analyzer, "allowlist")
+}
+
+func TestIgnorePattern(t *testing.T) {
+	wd, err := filepath.Abs("testdata")
+	if err != nil {
+		t.Fatal(err)
+	}
+	setFlag(t, "ignore-pattern", `^(deprecated:|TEST )|^scaffold %x|: %w$`)
+	analysistest.Run(t, wd, duperrormsg.Analyzer, "ignorepattern")
+
+	// Patterns that don't compile fail the analysis
+	var rec recorder
+	analysistest.Run(&rec, wd, duperrormsg.NewAnalyzer(duperrormsg.Options{IgnorePattern: "(deprecated"}), "noimports")
+	if !rec.contains("invalid ignore pattern") {
+		t.Errorf("expected an invalid ignore pattern error, got %v", rec.errors)
+	}
+}
+
+func TestEquivalence(t *testing.T) {
+	wd, err := filepath.Abs("testdata")
+	if err != nil {
+		t.Fatal(err)
+	}
+	setFlag(t, "equivalence", filepath.Join(wd, "equivalence.txt"))
+	setFlag(t, "ignore-case", "true")
+	analysistest.Run(t, wd, duperrormsg.Analyzer, "equivalence")
+
+	// Groups need at least two messages, and the file has to exist
+	f := duperrormsg.Analyzer.Flags.Lookup("equivalence")
+	if err := f.Value.Set(filepath.Join(wd, "equivalence-single.txt")); err == nil || !strings.Contains(err.Error(), `equivalence-single.txt:1: equivalence group "not found" needs at least two messages`) {
+		t.Errorf("expected an error for a group of one, got %v", err)
+	}
+	if err := f.Value.Set(filepath.Join(wd, "missing.txt")); err == nil {
+		t.Error("expected an error for a missing file")
+	}
+}
+
+func TestPublicAPIOnly(t *testing.T) {
+	wd, err := filepath.Abs("testdata")
+	if err != nil {
+		t.Fatal(err)
+	}
+	setFlag(t, "public-api-only", "true")
+	analysistest.Run(t, wd, duperrormsg.Analyzer, "publicapi")
+}
+
+func TestQualifiedConstructors(t *testing.T) {
+	wd, err := filepath.Abs("testdata")
+	if err != nil {
+		t.Fatal(err)
+	}
+	setFlag(t, "constructors", "wrap@1, problem, customctors/problems.Report, customctors/problems.Builder.Addf@1, customctors/catalog.New")
+	analysistest.Run(t, wd, duperrormsg.Analyzer, "customctors")
+
+	// Registered constructors return errors whatever their import path
+	// holds, so they aren't kept apart from errors.New as logs
+	setFlag(t, "separate-by-kind", "true")
+	analysistest.Run(t, wd, duperrormsg.Analyzer, "customctors/orders")
+}
+
+func TestOnlyFormatStrings(t *testing.T) {
+	wd, err := filepath.Abs("testdata")
+	if err != nil {
+		t.Fatal(err)
+	}
+	setFlag(t, "only-format-strings", "true")
+	analysistest.Run(t, wd, duperrormsg.Analyzer, "formatonly")
+}
+
+func TestSkipGenerated(t *testing.T) {
+	wd, err := filepath.Abs("testdata")
+	if err != nil {
+		t.Fatal(err)
+	}
+	analysistest.Run(t, wd, duperrormsg.Analyzer, "generated")
+
+	setFlag(t, "skip-generated", "false")
+	analysistest.Run(t, wd, duperrormsg.Analyzer, "includegenerated")
+}
+
+func TestSkipStringer(t *testing.T) {
+	wd, err := filepath.Abs("testdata")
+	if err != nil {
+		t.Fatal(err)
+	}
+	// Broad constructors such as fmt.Sprintf match the String methods
+	// stringer writes, even once generated files are checked
+	setFlag(t, "skip-generated", "false")
+	setFlag(t, "constructors", "fmt.Sprintf")
+	analysistest.Run(t, wd, duperrormsg.Analyzer, "stringer")
+
+	setFlag(t, "skip-stringer", "false")
+	analysistest.Run(t, wd, duperrormsg.Analyzer, "includestringer")
+}
+
+func TestExcludePaths(t *testing.T) {
+	wd, err := filepath.Abs("testdata")
+	if err != nil {
+		t.Fatal(err)
+	}
+	analysistest.Run(t, wd, duperrormsg.Analyzer, "vendored/vendor/lib")
+
+	setFlag(t, "exclude-paths", "**/vendor/**,**/legacy_*.go")
+	analysistest.Run(t, wd, duperrormsg.Analyzer, "excludepaths")
+
+	// Malformed patterns fail the analysis
+	var rec recorder
+	analysistest.Run(&rec, wd, duperrormsg.NewAnalyzer(duperrormsg.Options{ExcludePaths: []string{"**/[vendor/**"}}), "noimports")
+	if !rec.contains(`invalid exclude path "**/[vendor/**"`) {
+		t.Errorf("expected an invalid exclude path error, got %v", rec.errors)
+	}
+}
+
+func TestIncludeTestify(t *testing.T) {
+	wd, err := filepath.Abs("testdata")
+	if err != nil {
+		t.Fatal(err)
+	}
+	setFlag(t, "include-tests", "true")
+	setFlag(t, "include-testify", "true")
+	results := analysistest.Run(t, wd, duperrormsg.Analyzer, "testify")
+
+	for _, result := range results {
+		for _, diag := range result.Diagnostics {
+			if diag.Category != duperrormsg.CategoryTestifyExpect {
+				t.Errorf("unexpected category %q for %q", diag.Category, diag.Message)
+			}
+		}
+	}
+}
+
+func TestCollapseConsecutive(t *testing.T) {
+	wd, err := filepath.Abs("testdata")
+	if err != nil {
+		t.Fatal(err)
+	}
+	setFlag(t, "collapse-consecutive", "true")
+	analysistest.Run(t, wd, duperrormsg.Analyzer, "consecutive")
+}
+
+func TestExportedOnly(t *testing.T) {
+	wd, err := filepath.Abs("testdata")
+	if err != nil {
+		t.Fatal(err)
+	}
+	setFlag(t, "exported-only", "true")
+	analysistest.Run(t, wd, duperrormsg.Analyzer, "exportedonly")
+}
+
+func TestSummary(t *testing.T) {
+	wd, err := filepath.Abs("testdata")
+	if err != nil {
+		t.Fatal(err)
+	}
+	setFlag(t, "summary", "true")
+	results := analysistest.Run(t, wd, duperrormsg.Analyzer, "summary")
+
+	var summaries int
+	for _, diag := range results[0].Diagnostics {
+		if diag.Category == duperrormsg.CategorySummary {
+			summaries++
+		}
+	}
+	if summaries != 1 {
+		t.Errorf("expected one summary, got %d", summaries)
+	}
+
+	// Other categories of diagnostics aren't counted as duplicates
+	setFlag(t, "flag-stdlib-collisions", "true")
+	setFlag(t, "split-on-colon", "true")
+	var rec recorder
+	results = analysistest.Run(&rec, wd, duperrormsg.Analyzer, "summary")
+	for _, diag := range results[0].Diagnostics {
+		if diag.Category == duperrormsg.CategorySummary && diag.Message != "found 2 duplicate message groups covering 5 call sites" {
+			t.Errorf("unexpected summary %q", diag.Message)
+		}
+	}
+}
+
+func TestFlagStdlibCollisions(t *testing.T) {
+	wd, err := filepath.Abs("testdata")
+	if err != nil {
+		t.Fatal(err)
+	}
+	setFlag(t, "flag-stdlib-collisions", "true")
+	analysistest.Run(t, wd, duperrormsg.Analyzer, "stdlib")
+}
+
+func TestStripTrailingPunctuation(t *testing.T) {
+	wd, err := filepath.Abs("testdata")
+	if err != nil {
+		t.Fatal(err)
+	}
+	setFlag(t, "strip-trailing-punctuation", "true")
+	analysistest.Run(t, wd, duperrormsg.Analyzer, "trailingpunct")
+}
+
+func TestReportSingletons(t *testing.T) {
+	wd, err := filepath.Abs("testdata")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// Only duplicates are reported by default
+	var rec recorder
+	results := analysistest.Run(&rec, wd, duperrormsg.Analyzer, "singletons")
+	for _, diag := range results[0].Diagnostics {
+		if diag.Category == duperrormsg.CategoryInventory {
+			t.Errorf("unexpected inventory diagnostic %q", diag.Message)
+		}
+	}
+
+	setFlag(t, "report-singletons", "true")
+	analysistest.Run(t, wd, duperrormsg.Analyzer, "singletons")
+}
+
+func TestStripTrailingParens(t *testing.T) {
+	wd, err := filepath.Abs("testdata")
+	if err != nil {
+		t.Fatal(err)
+	}
+	setFlag(t, "strip-trailing-parens", "true")
+	analysistest.Run(t, wd, duperrormsg.Analyzer, "trailingparens")
+}
+
+func TestSplitOnColon(t *testing.T) {
+	wd, err := filepath.Abs("testdata")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// Tails are only compared when asked for
+	var rec recorder
+	results := analysistest.Run(&rec, wd, duperrormsg.Analyzer, "tails")
+	for _, diag := range results[0].Diagnostics {
+		if diag.Category == duperrormsg.CategoryTail {
+			t.Errorf("unexpected tail diagnostic %q", diag.Message)
+		}
+	}
+
+	setFlag(t, "split-on-colon", "true")
+	results = analysistest.Run(t, wd, duperrormsg.Analyzer, "tails")
+	for _, diag := range results[0].Diagnostics {
+		if diag.Category != duperrormsg.CategoryTail {
+			continue
+		}
+		if strings.Contains(diag.Message, "permission denied") && len(diag.Related) != 2 {
+			t.Errorf("expected 2 related locations for %q, got %d", diag.Message, len(diag.Related))
+		}
+	}
+}
+
+func TestConstructs(t *testing.T) {
+	wd, err := filepath.Abs("testdata")
+	if err != nil {
+		t.Fatal(err)
+	}
+	results := analysistest.Run(t, wd, duperrormsg.Analyzer, "constructs")
+
+	// Each other occurrence names its construct
+	var related []string
+	for _, diag := range results[0].Diagnostics {
+		if !strings.Contains(diag.Message, "mixed constructs") {
+			continue
+		}
+		for _, r := range diag.Related {
+			related = append(related, r.Message)
+		}
+	}
+	want := []string{"also used here (errors.New)", "also used here (fmt.Errorf)"}
+	if !reflect.DeepEqual(related, want) {
+		t.Errorf("unexpected related messages %q, want %q", related, want)
+	}
+}
+
+func TestAssertions(t *testing.T) {
+	wd, err := filepath.Abs("testdata")
+	if err != nil {
+		t.Fatal(err)
+	}
+	setFlag(t, "include-tests", "true")
+	analysistest.Run(t, wd, duperrormsg.Analyzer, "assertions")
+}
+
+func TestIgnoreTestHelpers(t *testing.T) {
+	wd, err := filepath.Abs("testdata")
+	if err != nil {
+		t.Fatal(err)
+	}
+	setFlag(t, "include-tests", "true")
+	setFlag(t, "ignore-test-helpers", "true")
+	analysistest.Run(t, wd, duperrormsg.Analyzer, "testhelpers")
+
+	setFlag(t, "helper-suffixes", "Fixture")
+	analysistest.Run(t, wd, duperrormsg.Analyzer, "helpersuffixes")
+}
+
+func TestConstructors(t *testing.T) {
+	wd, err := filepath.Abs("testdata")
+	if err != nil {
+		t.Fatal(err)
+	}
+	setFlag(t, "include-tests", "true")
+	setFlag(t, "constructors", "assertNoError@2, expectOK@1")
+	analysistest.Run(t, wd, duperrormsg.Analyzer, "helpers")
+}
+
+// faultDetector recognizes store.Fault(table, format, args...) calls
+type faultDetector struct{}
+
+func (faultDetector) Detect(call *ast.CallExpr, pass *analysis.Pass) (string, ast.Expr, bool, bool) {
+	sel, ok := call.Fun.(*ast.SelectorExpr)
+	if !ok || sel.Sel.Name != "Fault" || len(call.Args) < 2 {
+		return "", nil, false, false
+	}
+	return "store.Fault", call.Args[1], true, true
+}
+
+func TestDetectors(t *testing.T) {
+	wd, err := filepath.Abs("testdata")
+	if err != nil {
+		t.Fatal(err)
+	}
+	analyzer := duperrormsg.NewAnalyzer(duperrormsg.Options{
+		Detectors: []duperrormsg.ConstructDetector{faultDetector{}},
+	})
+	results := analysistest.Run(t, wd, analyzer, "detectors")
+
+	res := results[0].Result.(*duperrormsg.Result)
+	if n := len(res.Duplicates()); n != 1 {
+		t.Fatalf("expected one duplicate, got %d", n)
+	}
+	for _, group := range res.Duplicates() {
+		if got := group.Occurrences[0].Construct; got != "store.Fault" {
+			t.Errorf("unexpected construct %q", got)
+		}
+	}
+}
+
+func TestThreshold(t *testing.T) {
+	wd, err := filepath.Abs("testdata")
+	if err != nil {
+		t.Fatal(err)
+	}
+	analysistest.Run(t, wd, duperrormsg.Analyzer, "pairs")
+
+	setFlag(t, "threshold", "3")
+	results := analysistest.Run(t, wd, duperrormsg.Analyzer, "threshold")
+	checkDuplicates(t, results[0])
+
+	// Thresholds below 2 would report every message
+	for _, threshold := range []int{1, -1} {
+		var rec recorder
+		analysistest.Run(&rec, wd, duperrormsg.NewAnalyzer(duperrormsg.Options{Threshold: threshold}), "noimports")
+		if !rec.contains(fmt.Sprintf("invalid threshold %d", threshold)) {
+			t.Errorf("expected an invalid threshold error, got %v", rec.errors)
+		}
+	}
+}
+
+func TestSeparateByKind(t *testing.T) {
+	wd, err := filepath.Abs("testdata")
+	if err != nil {
+		t.Fatal(err)
+	}
+	setFlag(t, "separate-by-kind", "true")
+	analysistest.Run(t, wd, duperrormsg.Analyzer, "kinds")
+}
+
+func TestSkipIfAllInOneFunc(t *testing.T) {
+	wd, err := filepath.Abs("testdata")
+	if err != nil {
+		t.Fatal(err)
+	}
+	setFlag(t, "skip-if-all-in-one-func", "true")
+	results := analysistest.Run(t, wd, duperrormsg.Analyzer, "onefunc")
+	checkDuplicates(t, results[0])
+}
+
+// checkDuplicates verifies the result lists as many duplicates as were
+// reported, so drivers rendering groups agree with the diagnostics
+func checkDuplicates(t *testing.T, res *analysistest.Result) {
+	t.Helper()
+	dups := res.Result.(*duperrormsg.Result).Duplicates()
+	if len(dups) != len(res.Diagnostics) {
+		t.Errorf("expected %d duplicate groups, got %d", len(res.Diagnostics), len(dups))
+	}
+}
+
+func TestHighlightCopyPaste(t *testing.T) {
+	wd, err := filepath.Abs("testdata")
+	if err != nil {
+		t.Fatal(err)
+	}
+	setFlag(t, "highlight-copy-paste", "true")
+	analysistest.Run(t, wd, duperrormsg.Analyzer, "copypaste")
+}
+
+func TestDedupeWindow(t *testing.T) {
+	wd, err := filepath.Abs("testdata")
+	if err != nil {
+		t.Fatal(err)
+	}
+	setFlag(t, "dedupe-window", "3")
+	analysistest.Run(t, wd, duperrormsg.Analyzer, "window")
+
+	// Groups merged by similarity are split into clusters with spellings of
+	// their own
+	setFlag(t, "similarity", "3")
+	results := analysistest.Run(t, wd, duperrormsg.Analyzer, "windowsimilar")
+	for _, diag := range results[0].Diagnostics {
+		if diag.Category == duperrormsg.CategoryDuplicate && len(diag.SuggestedFixes) == 0 {
+			t.Errorf("expected a fix for %q", diag.Message)
+		}
+	}
+}
+
+func TestSimilarity(t *testing.T) {
+	wd, err := filepath.Abs("testdata")
+	if err != nil {
+		t.Fatal(err)
+	}
+	setFlag(t, "similarity", "3")
+	analysistest.Run(t, wd, duperrormsg.Analyzer, "similar")
+}
+
+func TestMaxFuzzyMessages(t *testing.T) {
+	wd, err := filepath.Abs("testdata")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// The package has more distinct messages than the cap, so only exact
+	// duplicates are reported
+	var buf bytes.Buffer
+	analyzer := duperrormsg.NewAnalyzer(duperrormsg.Options{
+		Similarity:       3,
+		MaxFuzzyMessages: 2,
+		DebugLogger:      log.New(&buf, "", 0),
+	})
+	var rec recorder
+	results := analysistest.Run(&rec, wd, analyzer, "similar")
+	for _, diag := range results[0].Diagnostics {
+		if diag.Category == duperrormsg.CategorySimilar {
+			t.Errorf("unexpected similar diagnostic %q", diag.Message)
+		}
+	}
+	if !strings.Contains(buf.String(), "similar: skipping -similarity, 8 distinct messages exceed -max-fuzzy-messages=2") {
+		t.Errorf("missing warning, got %q", buf.String())
+	}
+
+	// Under the cap messages are compared as usual
+	buf.Reset()
+	analyzer = duperrormsg.NewAnalyzer(duperrormsg.Options{
+		Similarity:       3,
+		MaxFuzzyMessages: 8,
+		DebugLogger:      log.New(&buf, "", 0),
+	})
+	analysistest.Run(t, wd, analyzer, "similar")
+	if buf.Len() > 0 {
+		t.Errorf("unexpected warning %q", buf.String())
+	}
+}
+
+func TestReportAtLiteral(t *testing.T) {
+	wd, err := filepath.Abs("testdata")
+	if err != nil {
+		t.Fatal(err)
+	}
+	setFlag(t, "report-at-literal", "true")
+	results := analysistest.Run(t, wd, duperrormsg.Analyzer, "literalpos")
+
+	// Every position, primary or related, is the opening quote of a literal
+	src, err := os.ReadFile(filepath.Join(wd, "src", "literalpos", "literalpos.go"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	fset := results[0].Pass.Fset
+	for _, diag := range results[0].Diagnostics {
+		positions := []token.Pos{diag.Pos}
+		for _, related := range diag.Related {
+			positions = append(positions, related.Pos)
+		}
+		if len(positions) != 5 {
+			t.Fatalf("expected 5 positions, got %d", len(positions))
+		}
+		for _, pos := range positions {
+			if p := fset.Position(pos); src[p.Offset] != '"' {
+				t.Errorf("%v points at %q", p, src[p.Offset:p.Offset+5])
+			}
+		}
+
+		// A concatenation spread over several lines is reported at its first
+		// operand, never at the literals continuing it
+		var concat int
+		for _, pos := range positions {
+			p := fset.Position(pos)
+			switch rest := string(src[p.Offset:]); {
+			case strings.HasPrefix(rest, `"cache" +`):
+				concat++
+			case !strings.HasPrefix(rest, `"cache miss"`):
+				t.Errorf("%v points inside a concatenation at %q", p, src[p.Offset:p.Offset+6])
+			}
+		}
+		if concat != 1 {
+			t.Errorf("expected the concatenation to be reported once, got %d", concat)
+		}
+	}
+}
+
+func TestReportUniqueOnce(t *testing.T) {
+	wd, err := filepath.Abs("testdata")
+	if err != nil {
+		t.Fatal(err)
+	}
+	analysistest.Run(t, wd, duperrormsg.Analyzer, "samepos")
+
+	setFlag(t, "report-unique-once", "false")
+	analysistest.Run(t, wd, duperrormsg.Analyzer, "sameposall")
+}
+
+func TestLimitPerMessage(t *testing.T) {
+	wd, err := filepath.Abs("testdata")
+	if err != nil {
+		t.Fatal(err)
+	}
+	setFlag(t, "limit-per-message", "2")
+	results := analysistest.Run(t, wd, duperrormsg.Analyzer, "limit")
+
+	for _, diag := range results[0].Diagnostics {
+		if len(diag.Related) > 2 {
+			t.Errorf("%q lists %d other occurrences", diag.Message, len(diag.Related))
+		}
+	}
+}
+
+func TestMultipleFiles(t *testing.T) {
+	wd, err := filepath.Abs("testdata")
+	if err != nil {
+		t.Fatal(err)
+	}
+	results := analysistest.Run(t, wd, duperrormsg.Analyzer, "multifile")
+
+	// Every other occurrence is listed with its own file and position
+	var related []string
+	for _, diag := range results[0].Diagnostics {
+		if !strings.Contains(diag.Message, "files") {
+			continue
+		}
+		for _, r := range diag.Related {
+			pos := results[0].Pass.Fset.Position(r.Pos)
+			related = append(related, fmt.Sprintf("%s:%d:%d", filepath.Base(pos.Filename), pos.Line, pos.Column))
+		}
+	}
+	if got, want := strings.Join(related, " "), "b.go:7:10 b.go:14:10"; got != want {
+		t.Errorf("unexpected related positions %q, want %q", got, want)
+	}
+}
+
+func TestFindings(t *testing.T) {
+	wd, err := filepath.Abs("testdata")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// A wrapper requiring the checker, as a JSON reporting driver would use
+	var findings []duperrormsg.Finding
+	wrapper := &analysis.Analyzer{
+		Name:     "duperrorjson",
+		Doc:      "collects duplicate error messages",
+		Requires: []*analysis.Analyzer{duperrormsg.Analyzer},
+		Run: func(pass *analysis.Pass) (interface{}, error) {
+			findings = append(findings, duperrormsg.Findings(pass)...)
+			return nil, nil
+		},
+	}
+	analysistest.Run(t, wd, wrapper, "findings")
+
+	if len(findings) != 1 {
+		t.Fatalf("expected one finding, got %d", len(findings))
+	}
+	f := findings[0]
+	if f.Message != `duplicate error message "disk full" used at 3 locations (mixed constructs)` {
+		t.Errorf("unexpected message %q", f.Message)
+	}
+	if f.Construct != "errors.New" {
+		t.Errorf("unexpected construct %q", f.Construct)
+	}
+	if f.Pos.Line != 9 || len(f.Duplicates) != 2 || f.Duplicates[0].Line != 10 || f.Duplicates[1].Line != 11 {
+		t.Errorf("unexpected positions %v and %v", f.Pos, f.Duplicates)
+	}
+	if f.Files != 1 {
+		t.Errorf("expected one file, got %d", f.Files)
+	}
+	if _, err := json.Marshal(findings); err != nil {
+		t.Fatal(err)
+	}
+
+	// Occurrences spread across files are counted per file. The wrapper
+	// doesn't report the diagnostics the fixture expects.
+	findings = nil
+	var rec recorder
+	analysistest.Run(&rec, wd, wrapper, "multifile")
+	files := make(map[string]int)
+	for _, f := range findings {
+		files[f.Message] = f.Files
+	}
+	want := map[string]int{
+		`duplicate error message "missing name" used at 3 locations in 2 files (errors.New)`: 2,
+		`duplicate error message "cannot open %s" used at 2 locations (fmt.Errorf)`:          1,
+	}
+	if !reflect.DeepEqual(files, want) {
+		t.Errorf("unexpected file counts %v, want %v", files, want)
+	}
+	out, err := json.Marshal(findings[0])
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !strings.Contains(string(out), `"files":`) {
+		t.Errorf("expected a files field in %s", out)
+	}
+
+	// Format strings with verbs are flagged, since extracting them is unsafe
+	verbs := make(map[string]bool)
+	for _, f := range findings {
+		verbs[f.Message] = f.HasFormatVerbs
+	}
+	wantVerbs := map[string]bool{
+		`duplicate error message "missing name" used at 3 locations in 2 files (errors.New)`: false,
+		`duplicate error message "cannot open %s" used at 2 locations (fmt.Errorf)`:          true,
+	}
+	if !reflect.DeepEqual(verbs, wantVerbs) {
+		t.Errorf("unexpected format verbs %v, want %v", verbs, wantVerbs)
+	}
+}
+
+func TestDiagnosticsFor(t *testing.T) {
+	wd, err := filepath.Abs("testdata")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	cases := []struct {
+		pkg  string
+		opts duperrormsg.Options
+	}{
+		{pkg: "fix"},
+		{pkg: "multifile"},
+		{pkg: "limit", opts: duperrormsg.Options{LimitPerMessage: 2}},
+		{pkg: "onefunc", opts: duperrormsg.Options{SkipIfAllInOneFunc: true}},
+	}
+	for _, tc := range cases {
+		t.Run(tc.pkg, func(t *testing.T) {
+			var rec recorder
+			results := analysistest.Run(&rec, wd, duperrormsg.NewAnalyzer(tc.opts), tc.pkg)
+			result := results[0].Result.(*duperrormsg.Result)
+
+			// The same options produce exactly what the analyzer reported, apart
+			// from the URL drivers resolve from each category
+			want := results[0].Diagnostics
+			for i := range want {
+				want[i].URL = ""
+			}
+			got, err := duperrormsg.DiagnosticsFor(result, tc.opts)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if len(got) == 0 || !reflect.DeepEqual(got, want) {
+				t.Errorf("diagnostics differ from the analyzer's\ngot:  %v\nwant: %v", got, want)
+			}
+		})
+	}
+
+	// Reporting options apply without running the analysis again
+	results := analysistest.Run(t, wd, duperrormsg.Analyzer, "multifile")
+	result := results[0].Result.(*duperrormsg.Result)
+	diags, err := duperrormsg.DiagnosticsFor(result, duperrormsg.Options{LimitPerMessage: 1})
+	if err != nil {
+		t.Fatal(err)
+	}
+	for _, diag := range diags {
+		if len(diag.Related) > 1 {
+			t.Errorf("%q lists %d other occurrences", diag.Message, len(diag.Related))
+		}
+	}
+
+	// Options the analyzer rejects are rejected here too
+	for _, opts := range []duperrormsg.Options{{Threshold: 1}, {Threshold: -1}, {Scope: "module"}} {
+		if diags, err := duperrormsg.DiagnosticsFor(result, opts); err == nil {
+			t.Errorf("expected an error for %+v, got %d diagnostics", opts, len(diags))
+		}
+	}
+
+	if diags, err := duperrormsg.DiagnosticsFor(&duperrormsg.Result{}, duperrormsg.Options{}); err != nil || diags != nil {
+		t.Errorf("expected no diagnostics for an empty result, got %v, %v", diags, err)
+	}
+}
+
+func TestCheckSource(t *testing.T) {
+	wd, err := filepath.Abs("testdata")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// A file checked on its own matches what the analyzer finds in it
+	var want []duperrormsg.Finding
+	wrapper := &analysis.Analyzer{
+		Name:     "duperrorsource",
+		Doc:      "collects duplicate error messages",
+		Requires: []*analysis.Analyzer{duperrormsg.Analyzer},
+		Run: func(pass *analysis.Pass) (interface{}, error) {
+			want = append(want, duperrormsg.Findings(pass)...)
+			return nil, nil
+		},
+	}
+	analysistest.Run(t, wd, wrapper, "findings")
+
+	filename := filepath.Join(wd, "src", "findings", "findings.go")
+	src, err := os.ReadFile(filename)
+	if err != nil {
+		t.Fatal(err)
+	}
+	got, err := duperrormsg.CheckSource(filename, src)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("unexpected findings\ngot:  %v\nwant: %v", got, want)
+	}
+
+	// Options apply, and imports that can't be resolved are tolerated
+	src = []byte(`package main
+
+import (
+	"errors"
+
+	"example.com/missing"
+)
+
+func main() {
+	missing.Run(errors.New("Not Found"), errors.New("not found"))
+}
+`)
+	got, err = duperrormsg.Options{IgnoreCase: true}.CheckSource("main.go", src)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(got) != 1 || got[0].Message != `duplicate error message "Not Found" used at 2 locations (errors.New)` {
+		t.Errorf("unexpected findings %v", got)
+	}
+
+	if _, err := duperrormsg.CheckSource("broken.go", []byte("package")); err == nil {
+		t.Error("expected an error for a file that doesn't parse")
+	}
+	if _, err := (duperrormsg.Options{Scope: "module"}).CheckSource("main.go", src); err == nil {
+		t.Error("expected an error for an unknown scope")
+	}
+}
+
+func TestCountLocations(t *testing.T) {
+	group := &duperrormsg.Group{Occurrences: []duperrormsg.Occurrence{
+		{Pos: token.Position{Filename: "a.go"}},
+		{Pos: token.Position{Filename: "b.go"}},
+		{Pos: token.Position{Filename: "a.go"}},
+	}}
+	if got := duperrormsg.CountLocations(len(group.Occurrences), group.Files()); got != "3 locations in 2 files" {
+		t.Errorf("got %q", got)
+	}
+	if got := duperrormsg.CountLocations(2, 1); got != "2 locations" {
+		t.Errorf("got %q", got)
+	}
+	if got := duperrormsg.CountLocations(1, 1); got != "1 location" {
+		t.Errorf("got %q", got)
+	}
+}
+
+func TestGroupHash(t *testing.T) {
+	wd, err := filepath.Abs("testdata")
+	if err != nil {
+		t.Fatal(err)
+	}
+	hashes := func() map[string]string {
+		results := analysistest.Run(t, wd, duperrormsg.Analyzer, "hashes")
+		out := make(map[string]string)
+		for _, group := range results[0].Result.(*duperrormsg.Result).Groups {
+			out[group.Text] = group.GroupHash()
+		}
+		return out
+	}
+
+	// Hashes are stable across runs and distinct between groups
+	first, second := hashes(), hashes()
+	seen := make(map[string]string)
+	for text, hash := range first {
+		if second[text] != hash {
+			t.Errorf("hash of %q changed from %s to %s", text, hash, second[text])
+		}
+		if other, ok := seen[hash]; ok {
+			t.Errorf("%q and %q share hash %s", text, other, hash)
+		}
+		seen[hash] = text
+	}
+	if len(first) != 3 {
+		t.Errorf("expected 3 groups, got %d", len(first))
+	}
+
+	// Positions and the order of constructs don't matter, their kinds do
+	a := &duperrormsg.Group{Message: "disk full", Occurrences: []duperrormsg.Occurrence{
+		{Construct: "errors.New"}, {Construct: "fmt.Errorf"},
+	}}
+	b := &duperrormsg.Group{Message: "disk full", Occurrences: []duperrormsg.Occurrence{
+		{Construct: "fmt.Errorf", Pos: token.Position{Line: 3}}, {Construct: "errors.New"}, {Construct: "errors.New"},
+	}}
+	c := &duperrormsg.Group{Message: "disk full", Occurrences: []duperrormsg.Occurrence{
+		{Construct: "errors.New"},
+	}}
+	if a.GroupHash() != b.GroupHash() {
+		t.Error("expected equal hashes regardless of positions and order")
+	}
+	if a.GroupHash() == c.GroupHash() {
+		t.Error("expected construct kinds to change the hash")
+	}
+}
+
+func TestNolint(t *testing.T) {
+	wd, err := filepath.Abs("testdata")
+	if err != nil {
+		t.Fatal(err)
+	}
+	analysistest.Run(t, wd, duperrormsg.Analyzer, "nolint")
+}
+
+func TestDebugLogger(t *testing.T) {
+	wd, err := filepath.Abs("testdata")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	var buf bytes.Buffer
+	analyzer := duperrormsg.NewAnalyzer(duperrormsg.Options{
+		Verbose:     true,
+		DebugLogger: log.New(&buf, "", 0),
+	})
+	analysistest.Run(t, wd, analyzer, "nolint")
+
+	output := buf.String()
+	for _, want := range []string{
+		`nolint.go:9:2: found "upstream unavailable" from errors.New`,
+		`nolint.go:8:2: skipping "upstream unavailable" suppressed by nolint`,
+	} {
+		if !strings.Contains(output, want) {
+			t.Errorf("missing %q in debug output:\n%s", want, output)
+		}
+	}
+}
+
+func TestScopeFunction(t *testing.T) {
+	wd, err := filepath.Abs("testdata")
+	if err != nil {
+		t.Fatal(err)
+	}
+	analyzer := duperrormsg.NewAnalyzer(duperrormsg.Options{
+		Scope: duperrormsg.ScopeFunction,
+	})
+	analysistest.Run(t, wd, analyzer, "scope")
+
+	// Unknown scopes fail the analysis. The package has no imports, as a
+	// failure in a dependency would hide the cause.
+	var rec recorder
+	analysistest.Run(&rec, wd, duperrormsg.NewAnalyzer(duperrormsg.Options{Scope: "module"}), "noimports")
+	if !rec.contains(`unknown scope "module"`) {
+		t.Errorf("expected an unknown scope error, got %v", rec.errors)
+	}
+}
+
+func TestScopeFile(t *testing.T) {
+	wd, err := filepath.Abs("testdata")
+	if err != nil {
+		t.Fatal(err)
+	}
+	analyzer := duperrormsg.NewAnalyzer(duperrormsg.Options{
+		Scope: duperrormsg.ScopeFile,
+	})
+	analysistest.Run(t, wd, analyzer, "filescope")
+
+	// The message used once in each file is a duplicate within the package
+	var rec recorder
+	results := analysistest.Run(&rec, wd, duperrormsg.Analyzer, "filescope")
+	var found bool
+	for _, diag := range results[0].Diagnostics {
+		if strings.Contains(diag.Message, `already exists" used at 2 locations in 2 files`) {
+			found = true
+		}
+	}
+	if !found {
+		t.Errorf("expected the message shared by both files to be reported at package scope")
+	}
+}
+
+// recorder collects the errors reported by analysistest
+type recorder struct {
+	errors []string
+}
+
+func (r *recorder) Errorf(format string, args ...interface{}) {
+	r.errors = append(r.errors, fmt.Sprintf(format, args...))
+}
+
+func (r *recorder) contains(substr string) bool {
+	for _, err := range r.errors {
+		if strings.Contains(err, substr) {
+			return true
+		}
+	}
+	return false
+}
+
+func TestSuggestedFixes(t *testing.T) {
+	wd, err := filepath.Abs("testdata")
+	if err != nil {
+		t.Fatal(err)
+	}
+	analysistest.RunWithSuggestedFixes(t, wd, duperrormsg.Analyzer, "fix")
+
+	// Constructors called without a package selector get no fix
+	setFlag(t, "constructors", "errors.New")
+	results := analysistest.Run(t, wd, duperrormsg.Analyzer, "dotimport")
+	for _, diag := range results[0].Diagnostics {
+		if len(diag.SuggestedFixes) > 0 {
+			t.Errorf("unexpected fix for %q", diag.Message)
+		}
+	}
+}
+
+func TestWorkers(t *testing.T) {
+	wd, err := filepath.Abs("testdata")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// Sharded extraction reports exactly what a sequential pass does
+	diagnostics := func(workers int) []string {
+		analyzer := duperrormsg.NewAnalyzer(duperrormsg.Options{Workers: workers})
+		var out []string
+		for _, res := range analysistest.Run(t, wd, analyzer, "tests") {
+			for _, diag := range res.Diagnostics {
+				out = append(out, fmt.Sprintf("%v: %s", res.Pass.Fset.Position(diag.Pos), diag.Message))
+			}
+		}
+		return out
+	}
+	sequential, sharded := diagnostics(1), diagnostics(8)
+	if strings.Join(sequential, "\n") != strings.Join(sharded, "\n") {
+		t.Errorf("sharded diagnostics differ\nsequential:\n%s\nsharded:\n%s",
+			strings.Join(sequential, "\n"), strings.Join(sharded, "\n"))
+	}
+}
+
+func BenchmarkWorkers(b *testing.B) {
+	pkgs := loadLarge(b)
+
+	// Speedups show up to the number of available CPUs
+	for _, workers := range []int{1, 2, 4, 8} {
+		b.Run(fmt.Sprintf("workers=%d", workers), func(b *testing.B) {
+			analyzer := duperrormsg.NewAnalyzer(duperrormsg.Options{Workers: workers})
+			for b.Loop() {
+				if _, err := checker.Analyze([]*analysis.Analyzer{analyzer}, pkgs, nil); err != nil {
+					b.Fatal(err)
+				}
+			}
+		})
+	}
+}
+
+func BenchmarkMaxFuzzyMessages(b *testing.B) {
+	pkgs := loadLarge(b)
+
+	// The synthetic package has 5k distinct messages
+	for _, limit := range []int{0, 1000} {
+		b.Run(fmt.Sprintf("limit=%d", limit), func(b *testing.B) {
+			analyzer := duperrormsg.NewAnalyzer(duperrormsg.Options{
+				Similarity:       2,
+				MaxFuzzyMessages: limit,
+				DebugLogger:      log.New(io.Discard, "", 0),
+			})
+			for b.Loop() {
+				if _, err := checker.Analyze([]*analysis.Analyzer{analyzer}, pkgs, nil); err != nil {
+					b.Fatal(err)
+				}
+			}
+		})
+	}
+}
+
+// loadLarge loads a synthetic package with 40k calls, mostly errors split
+// across functions
+func loadLarge(b *testing.B) []*packages.Package {
+	b.Helper()
+	dir := b.TempDir()
+	var src strings.Builder
+	src.WriteString("package large\n\nimport \"errors\"\n\n")
+	src.WriteString("func NewLargeError(code int, msg string) error { return errors.New(msg) }\n\n")
+	for fn := 0; fn < 400; fn++ {
+		fmt.Fprintf(&src, "func f%d() {\n", fn)
+		for i := 0; i < 100; i++ {
+			if i%2 == 0 {
+				fmt.Fprintf(&src, "\t_ = errors.New(\"message %d from %d\")\n", i, fn%50)
+			} else {
+				fmt.Fprintf(&src, "\t_ = NewLargeError(%d, \"message %d from %d\")\n", i, i, fn%50)
+			}
+		}
+		src.WriteString("}\n\n")
+	}
+	files := map[string]string{
+		"go.mod":   "module large\n\ngo 1.24\n",
+		"large.go": src.String(),
+	}
+	for name, content := range files {
+		if err := os.WriteFile(filepath.Join(dir, name), []byte(content), 0o644); err != nil {
+			b.Fatal(err)
+		}
+	}
+
+	pkgs, err := packages.Load(&packages.Config{Dir: dir, Mode: packages.LoadAllSyntax}, ".")
+	if err != nil {
+		b.Fatal(err)
+	}
+	if packages.PrintErrors(pkgs) > 0 {
+		b.Fatal("errors loading the synthetic package")
+	}
+	return pkgs
 }
@@ -0,0 +1,261 @@
+package duperrormsg
+
+import (
+	"fmt"
+	"go/ast"
+	"strconv"
+	"strings"
+)
+
+// enclosingFunc returns the innermost *ast.FuncDecl or *ast.FuncLit containing
+// the last node of stack, or nil when the node is at package scope.
+//
+// Function literals launched directly by a go or defer statement, as in
+// go func() { ... }(), belong to the function that started them.
+func enclosingFunc(stack []ast.Node) ast.Node {
+	for i := len(stack) - 2; i >= 0; i-- {
+		switch fn := stack[i].(type) {
+		case *ast.FuncDecl:
+			return fn
+		case *ast.FuncLit:
+			if isLaunched(stack, i) {
+				continue
+			}
+			return fn
+		}
+	}
+	return nil
+}
+
+// enclosingDecl returns the function declaration containing the last node of
+// stack, looking through any function literals, or nil at package scope.
+func enclosingDecl(stack []ast.Node) *ast.FuncDecl {
+	for i := len(stack) - 2; i >= 0; i-- {
+		if fn, ok := stack[i].(*ast.FuncDecl); ok {
+			return fn
+		}
+	}
+	return nil
+}
+
+// sentinelVar returns the package level variable initialized by the last node
+// of stack, as in var ErrNotFound = errors.New("not found").
+func sentinelVar(stack []ast.Node) *ast.Ident {
+	if len(stack) < 4 {
+		return nil
+	}
+	spec, ok := stack[len(stack)-2].(*ast.ValueSpec)
+	if !ok {
+		return nil
+	}
+	if _, ok := stack[len(stack)-4].(*ast.File); !ok {
+		return nil
+	}
+	for i, value := range spec.Values {
+		if value == stack[len(stack)-1] && i < len(spec.Names) {
+			return spec.Names[i]
+		}
+	}
+	return nil
+}
+
+// inOneFunc reports whether every location is inside the same function
+func inOneFunc(locations []ErrorInfo) bool {
+	fn := locations[0].Func
+	if fn == nil {
+		return false
+	}
+	for _, loc := range locations[1:] {
+		if loc.Func != fn {
+			return false
+		}
+	}
+	return true
+}
+
+// sharesFunc reports whether any two locations are inside the same function
+func sharesFunc(locations []ErrorInfo) bool {
+	seen := make(map[ast.Node]bool, len(locations))
+	for _, loc := range locations {
+		if loc.Func == nil {
+			continue
+		}
+		if seen[loc.Func] {
+			return true
+		}
+		seen[loc.Func] = true
+	}
+	return false
+}
+
+// sentinelNames lists the variables declared by locations, as in "ErrA and
+// ErrB", or returns an empty string when any location isn't a sentinel error.
+func sentinelNames(locations []ErrorInfo) string {
+	names := make([]string, 0, len(locations))
+	for _, loc := range locations {
+		if loc.Var == nil {
+			return ""
+		}
+		names = append(names, loc.Var.Name)
+	}
+	return joinList(names)
+}
+
+// quotedList quotes each message and joins them as in "a", "b" and "c"
+func quotedList(msgs []string) string {
+	quoted := make([]string, len(msgs))
+	for i, msg := range msgs {
+		quoted[i] = strconv.Quote(msg)
+	}
+	return joinList(quoted)
+}
+
+// joinList joins items as in "a, b and c"
+func joinList(items []string) string {
+	if len(items) < 2 {
+		return strings.Join(items, "")
+	}
+	return strings.Join(items[:len(items)-1], ", ") + " and " + items[len(items)-1]
+}
+
+// returnedFromExported reports whether the last node of stack is returned
+// directly by an exported function, or an exported method of an exported
+// type. Errors returned from function literals are internal even when the
+// literal is declared inside an exported function.
+func returnedFromExported(stack []ast.Node) bool {
+	i := len(stack) - 2
+	for i >= 0 {
+		if _, ok := stack[i].(*ast.ParenExpr); !ok {
+			break
+		}
+		i--
+	}
+	if i < 0 {
+		return false
+	}
+	if _, ok := stack[i].(*ast.ReturnStmt); !ok {
+		return false
+	}
+	for ; i >= 0; i-- {
+		switch fn := stack[i].(type) {
+		case *ast.FuncLit:
+			return false
+		case *ast.FuncDecl:
+			if recv := recvName(fn); recv != nil && !recv.IsExported() {
+				return false
+			}
+			return fn.Name.IsExported()
+		}
+	}
+	return false
+}
+
+// exportedFacing reports whether the last node of stack initializes the
+// exported package level variable sentinel, or appears anywhere in the body of
+// an exported function or an exported method of an exported type.
+func exportedFacing(stack []ast.Node, sentinel *ast.Ident) bool {
+	if sentinel != nil {
+		return sentinel.IsExported()
+	}
+	fn := enclosingDecl(stack)
+	if fn == nil {
+		return false
+	}
+	if recv := recvName(fn); recv != nil && !recv.IsExported() {
+		return false
+	}
+	return fn.Name.IsExported()
+}
+
+// isLaunched reports whether the function literal at stack[i] is immediately
+// called by a go or defer statement.
+func isLaunched(stack []ast.Node, i int) bool {
+	if i < 2 {
+		return false
+	}
+	call, ok := stack[i-1].(*ast.CallExpr)
+	if !ok || call.Fun != stack[i] {
+		return false
+	}
+	switch stack[i-2].(type) {
+	case *ast.GoStmt, *ast.DeferStmt:
+		return true
+	}
+	return false
+}
+
+// funcNamer names enclosing functions for the exported result
+type funcNamer struct {
+	files   []*ast.File
+	literal map[*ast.FuncLit]string
+}
+
+func newFuncNamer(files []*ast.File) *funcNamer {
+	return &funcNamer{
+		files: files,
+	}
+}
+
+// name returns "Func" or "Type.Method" for declarations and numbers function
+// literals within their declaration, e.g. "Func.func1".
+func (n *funcNamer) name(fn ast.Node) string {
+	switch fn := fn.(type) {
+	case *ast.FuncDecl:
+		return declName(fn)
+	case *ast.FuncLit:
+		if n.literal == nil {
+			n.nameLiterals()
+		}
+		return n.literal[fn]
+	}
+	return ""
+}
+
+func (n *funcNamer) nameLiterals() {
+	n.literal = make(map[*ast.FuncLit]string)
+	for _, file := range n.files {
+		for _, decl := range file.Decls {
+			prefix := "glob"
+			if fn, ok := decl.(*ast.FuncDecl); ok {
+				prefix = declName(fn)
+			}
+			count := 0
+			ast.Inspect(decl, func(node ast.Node) bool {
+				if lit, ok := node.(*ast.FuncLit); ok {
+					count++
+					n.literal[lit] = fmt.Sprintf("%s.func%d", prefix, count)
+				}
+				return true
+			})
+		}
+	}
+}
+
+func declName(fn *ast.FuncDecl) string {
+	if recv := recvName(fn); recv != nil {
+		return recv.Name + "." + fn.Name.Name
+	}
+	return fn.Name.Name
+}
+
+// recvName returns the type name of a method's receiver, or nil for functions
+func recvName(fn *ast.FuncDecl) *ast.Ident {
+	if fn.Recv == nil || len(fn.Recv.List) == 0 {
+		return nil
+	}
+	recv := fn.Recv.List[0].Type
+	for {
+		switch t := recv.(type) {
+		case *ast.StarExpr:
+			recv = t.X
+		case *ast.IndexExpr:
+			recv = t.X
+		case *ast.IndexListExpr:
+			recv = t.X
+		case *ast.Ident:
+			return t
+		default:
+			return nil
+		}
+	}
+}
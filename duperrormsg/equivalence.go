@@ -0,0 +1,80 @@
+package duperrormsg
+
+import (
+	"bufio"
+	"fmt"
+	"os"
+	"strings"
+)
+
+// loadEquivalences reads groups of messages treated as identical from the
+// file at path. Each line holds one message and groups are separated by blank
+// lines. Lines starting with # are comments, and surrounding whitespace is
+// ignored.
+func loadEquivalences(path string) ([][]string, error) {
+	f, err := os.Open(path)
+	if err != nil {
+		return nil, err
+	}
+	defer f.Close()
+
+	var groups [][]string
+	var group []string
+	start := 0 // Line of the group's first message
+	end := func() error {
+		if len(group) == 1 {
+			return fmt.Errorf("%s:%d: equivalence group %q needs at least two messages", path, start, group[0])
+		}
+		if len(group) > 0 {
+			groups = append(groups, group)
+			group = nil
+		}
+		return nil
+	}
+
+	scanner := bufio.NewScanner(f)
+	for line := 1; scanner.Scan(); line++ {
+		text := strings.TrimSpace(scanner.Text())
+		switch {
+		case strings.HasPrefix(text, "#"):
+		case text == "":
+			if err := end(); err != nil {
+				return nil, err
+			}
+		default:
+			if len(group) == 0 {
+				start = line
+			}
+			group = append(group, text)
+		}
+	}
+	if err := scanner.Err(); err != nil {
+		return nil, fmt.Errorf("reading %s: %v", path, err)
+	}
+	if err := end(); err != nil {
+		return nil, err
+	}
+	return groups, nil
+}
+
+// canonical maps every normalized member of the equivalence groups to the
+// first message of its group. Like the allowlist, members are added both as
+// written and with their formatting verbs normalized. When a message is in
+// more than one group, the first listed wins.
+func (o *Options) canonical() map[string]string {
+	canonical := make(map[string]string)
+	for _, group := range o.Equivalences {
+		if len(group) == 0 {
+			continue
+		}
+		first := o.Normalize(group[0])
+		for _, msg := range group {
+			for _, key := range []string{o.Normalize(msg), o.Normalize(normalizeVerbs(msg))} {
+				if _, ok := canonical[key]; !ok {
+					canonical[key] = first
+				}
+			}
+		}
+	}
+	return canonical
+}
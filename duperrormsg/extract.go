@@ -0,0 +1,65 @@
+package duperrormsg
+
+import (
+	"go/ast"
+	"runtime"
+	"sync"
+
+	"golang.org/x/tools/go/analysis"
+)
+
+// candidate is a call that may construct an error, along with what was
+// learned from its position in the syntax tree
+type candidate struct {
+	call     *ast.CallExpr
+	file     *ast.File
+	fn       ast.Node   // Enclosing function
+	sentinel *ast.Ident // Package level variable initialized by the call
+}
+
+// extracted is the outcome of extractErrorMessage for a candidate
+type extracted struct {
+	construct string
+	kind      string // Whether the construct returns an error, logs or panics
+	text      string
+	msg       string
+	arg       ast.Expr // Expression holding the message
+	verbs     bool     // Whether the message is a format string with verbs
+}
+
+// extractAll runs extractErrorMessage for every candidate, sharding them
+// across up to opts.Workers goroutines. Results are in candidate order.
+func extractAll(pass *analysis.Pass, opts *Options, locals localValues, candidates []candidate) []extracted {
+	results := make([]extracted, len(candidates))
+	extract := func(start, end int) {
+		for i := start; i < end; i++ {
+			results[i] = extractErrorMessage(pass, opts, locals, candidates[i].call)
+		}
+	}
+
+	workers := opts.Workers
+	if workers <= 0 {
+		workers = runtime.GOMAXPROCS(0)
+	}
+	if workers > len(candidates) {
+		workers = len(candidates)
+	}
+	if workers <= 1 {
+		extract(0, len(candidates))
+		return results
+	}
+
+	// Each worker fills its own contiguous range, so no locking is needed
+	size := (len(candidates) + workers - 1) / workers
+	var wg sync.WaitGroup
+	for start := 0; start < len(candidates); start += size {
+		end := min(start+size, len(candidates))
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			extract(start, end)
+		}()
+	}
+	wg.Wait()
+	return results
+}
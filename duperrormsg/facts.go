@@ -0,0 +1,99 @@
+package duperrormsg
+
+import (
+	"fmt"
+	"sort"
+	"strconv"
+
+	"golang.org/x/tools/go/analysis"
+)
+
+// packageMessages is exported for every analyzed package so that importers
+// can find messages duplicated across the package boundary.
+type packageMessages struct {
+	// Messages maps each normalized message to where it was first used
+	Messages map[string]string
+}
+
+func (*packageMessages) AFact() {}
+
+func (f *packageMessages) String() string {
+	return fmt.Sprintf("%d messages", len(f.Messages))
+}
+
+// factTypes returns the facts an analyzer declares. Drivers analyze every
+// dependency of a package declaring facts, so they're only declared when
+// they're used.
+func factTypes(crossPackage bool) []analysis.Fact {
+	if !crossPackage {
+		return nil
+	}
+	return []analysis.Fact{new(packageMessages)}
+}
+
+// crossPackageFlag sets CrossPackage along with the facts of the analyzer
+type crossPackageFlag struct {
+	opts     *Options
+	analyzer *analysis.Analyzer
+}
+
+func (f *crossPackageFlag) String() string {
+	if f.opts == nil {
+		return "false"
+	}
+	return strconv.FormatBool(f.opts.CrossPackage)
+}
+
+func (f *crossPackageFlag) Set(value string) error {
+	v, err := strconv.ParseBool(value)
+	if err != nil {
+		return err
+	}
+	f.opts.CrossPackage = v
+	f.analyzer.FactTypes = factTypes(v)
+	return nil
+}
+
+func (f *crossPackageFlag) IsBoolFlag() bool { return true }
+
+// exportMessages records the first position of every message in keys
+func exportMessages(pass *analysis.Pass, keys []groupKey, errorMap map[groupKey][]ErrorInfo) {
+	fact := &packageMessages{Messages: make(map[string]string, len(keys))}
+	for _, key := range keys {
+		if _, ok := fact.Messages[key.msg]; ok {
+			continue
+		}
+		fact.Messages[key.msg] = pass.Fset.Position(errorMap[key][0].reportPos()).String()
+	}
+	pass.ExportPackageFact(fact)
+}
+
+// reportImported reports messages also used by a directly imported package
+func reportImported(pass *analysis.Pass, opts *Options, keys []groupKey, errorMap map[groupKey][]ErrorInfo) {
+	seen := make(reported)
+	imports := pass.Pkg.Imports()
+	sort.Slice(imports, func(i, j int) bool {
+		return imports[i].Path() < imports[j].Path()
+	})
+
+	for _, key := range keys {
+		first := errorMap[key][0]
+		for _, imp := range imports {
+			var fact packageMessages
+			if !pass.ImportPackageFact(imp, &fact) {
+				continue
+			}
+			if pos, ok := fact.Messages[key.msg]; ok {
+				diag := analysis.Diagnostic{
+					Pos:      first.reportPos(),
+					Category: CategoryImported,
+					Message:  fmt.Sprintf("error message %q also used by imported package %s at %s", first.Text, imp.Path(), pos),
+				}
+				if seen.add(diag, opts) {
+					pass.Report(diag)
+				}
+				break
+			}
+		}
+	}
+}
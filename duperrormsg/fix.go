@@ -0,0 +1,222 @@
+package duperrormsg
+
+import (
+	"bytes"
+	"fmt"
+	"go/ast"
+	"go/format"
+	"go/token"
+	"go/types"
+	"sort"
+	"strings"
+	"unicode"
+
+	"golang.org/x/tools/go/analysis"
+)
+
+// maxNameWords caps how many words of a message make up a suggested variable name
+const maxNameWords = 5
+
+// fixer builds suggested fixes that extract duplicated messages into a shared
+// package level variable.
+type fixer struct {
+	pass  *analysis.Pass
+	files map[*token.File]*ast.File
+	names map[string]bool // Variable names already suggested
+}
+
+func newFixer(pass *analysis.Pass) *fixer {
+	files := make(map[*token.File]*ast.File, len(pass.Files))
+	for _, file := range pass.Files {
+		files[pass.Fset.File(file.Pos())] = file
+	}
+	return &fixer{
+		pass:  pass,
+		files: files,
+		names: make(map[string]bool),
+	}
+}
+
+// extractVar suggests declaring a variable such as
+//
+//	var errConnectionFailed = errors.New("connection failed")
+//
+// and referencing it from every errors.New or fmt.Errorf call in locations.
+// Groups with format verbs are skipped since their argument lists differ, as
+// are groups with other constructs, sentinel variables or test files.
+func (f *fixer) extractVar(locations []ErrorInfo) (analysis.SuggestedFix, bool) {
+	var pkgName string
+	for _, loc := range locations {
+		call, ok := loc.Pos.(*ast.CallExpr)
+		if !ok || len(call.Args) != 1 || loc.Var != nil || isTestFile(f.pass, call) {
+			return analysis.SuggestedFix{}, false
+		}
+		// Messages taken from elsewhere, as with fmt.Sprintf or a local
+		// variable, depend on values in scope at the call
+		if loc.Msg != call.Args[0] {
+			return analysis.SuggestedFix{}, false
+		}
+		if loc.Construct != "errors.New" && loc.Construct != "fmt.Errorf" || loc.HasFormatVerbs {
+			return analysis.SuggestedFix{}, false
+		}
+		// Constructors registered by name, or found by detectors, may be
+		// dot imported or called some other way
+		pkg, _, ok := packageCall(call)
+		if !ok {
+			return analysis.SuggestedFix{}, false
+		}
+		if pkgName == "" {
+			pkgName = pkg.Name
+		}
+	}
+	if !f.keepsImports(locations) {
+		return analysis.SuggestedFix{}, false
+	}
+
+	first := locations[0].Pos.(*ast.CallExpr)
+	_, sel, _ := packageCall(first)
+	file := f.files[f.pass.Fset.File(first.Pos())]
+	if file == nil {
+		return analysis.SuggestedFix{}, false
+	}
+
+	var msg bytes.Buffer
+	if err := format.Node(&msg, f.pass.Fset, first.Args[0]); err != nil {
+		return analysis.SuggestedFix{}, false
+	}
+	name := f.varName(locations[0].Text, locations)
+
+	// Edits are sorted by position, as the checker does when they are reported
+	var edits []analysis.TextEdit
+	for _, loc := range locations {
+		edits = append(edits, analysis.TextEdit{
+			Pos:     loc.Pos.Pos(),
+			End:     loc.Pos.End(),
+			NewText: []byte(name),
+		})
+	}
+	edits = append(edits, analysis.TextEdit{
+		Pos:     file.End(),
+		End:     file.End(),
+		NewText: []byte(fmt.Sprintf("\nvar %s = %s.%s(%s)\n", name, pkgName, sel.Sel.Name, msg.String())),
+	})
+	sort.Slice(edits, func(i, j int) bool {
+		return edits[i].Pos < edits[j].Pos
+	})
+	return analysis.SuggestedFix{
+		Message:   fmt.Sprintf("Extract message into %s", name),
+		TextEdits: edits,
+	}, true
+}
+
+// packageCall returns the package name and selector of a call written as
+// pkg.Func(...), or false for a call of any other shape
+func packageCall(call *ast.CallExpr) (*ast.Ident, *ast.SelectorExpr, bool) {
+	sel, ok := call.Fun.(*ast.SelectorExpr)
+	if !ok {
+		return nil, nil, false
+	}
+	pkg, ok := sel.X.(*ast.Ident)
+	if !ok {
+		return nil, nil, false
+	}
+	return pkg, sel, true
+}
+
+// keepsImports reports whether every file still uses the packages of the
+// rewritten calls afterwards. The file receiving the declaration always does.
+func (f *fixer) keepsImports(locations []ErrorInfo) bool {
+	declFile := f.pass.Fset.File(locations[0].Pos.Pos())
+
+	rewritten := make(map[*types.PkgName]map[*token.File]int)
+	for _, loc := range locations {
+		call := loc.Pos.(*ast.CallExpr)
+		ident, _, ok := packageCall(call)
+		if !ok {
+			return false
+		}
+		pkg, ok := f.pass.TypesInfo.Uses[ident].(*types.PkgName)
+		if !ok {
+			return false
+		}
+		if rewritten[pkg] == nil {
+			rewritten[pkg] = make(map[*token.File]int)
+		}
+		rewritten[pkg][f.pass.Fset.File(call.Pos())]++
+	}
+
+	for pkg, perFile := range rewritten {
+		for tf, count := range perFile {
+			if tf == declFile {
+				continue
+			}
+			uses := 0
+			ast.Inspect(f.files[tf], func(node ast.Node) bool {
+				if ident, ok := node.(*ast.Ident); ok && f.pass.TypesInfo.Uses[ident] == pkg {
+					uses++
+				}
+				return true
+			})
+			if uses <= count {
+				return false
+			}
+		}
+	}
+	return true
+}
+
+// varName derives an identifier from msg, e.g. errConnectionFailed, that's
+// unused in the package and not shadowed at any of locations
+func (f *fixer) varName(msg string, locations []ErrorInfo) string {
+	words := strings.FieldsFunc(msg, func(r rune) bool {
+		return !unicode.IsLetter(r) && !unicode.IsDigit(r)
+	})
+	if len(words) > maxNameWords {
+		words = words[:maxNameWords]
+	}
+
+	var name strings.Builder
+	name.WriteString("err")
+	for _, word := range words {
+		runes := []rune(strings.ToLower(word))
+		runes[0] = unicode.ToUpper(runes[0])
+		name.WriteString(string(runes))
+	}
+	if len(words) == 0 {
+		name.WriteString("Duplicate")
+	}
+
+	base := name.String()
+	candidate := base
+	for i := 2; f.taken(candidate, locations); i++ {
+		candidate = fmt.Sprintf("%s%d", base, i)
+	}
+	f.names[candidate] = true
+	return candidate
+}
+
+// taken reports whether name is already suggested or declared at package
+// level, imported by any file, or declared in a scope enclosing any of
+// locations, where it would shadow the new variable
+func (f *fixer) taken(name string, locations []ErrorInfo) bool {
+	if f.names[name] || f.pass.Pkg.Scope().Lookup(name) != nil {
+		return true
+	}
+	if f.pass.TypesInfo == nil {
+		return false
+	}
+	for _, file := range f.files {
+		if scope := f.pass.TypesInfo.Scopes[file]; scope != nil && scope.Lookup(name) != nil {
+			return true
+		}
+	}
+	for _, loc := range locations {
+		pos := loc.Pos.Pos()
+		if scope := f.pass.Pkg.Scope().Innermost(pos); scope != nil {
+			if _, obj := scope.LookupParent(name, pos); obj != nil {
+				return true
+			}
+		}
+	}
+	return false
+}
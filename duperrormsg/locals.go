@@ -0,0 +1,101 @@
+package duperrormsg
+
+import (
+	"go/ast"
+	"go/token"
+	"go/types"
+
+	"golang.org/x/tools/go/analysis"
+)
+
+// localValues maps local string variables assigned exactly once, where they
+// are declared, to the expression they're initialized with, as in
+// msg := fmt.Sprintf("user %s banned", name).
+type localValues map[*types.Var]ast.Expr
+
+// findLocals collects the local variables in pass that are initialized once
+// and never assigned again or have their address taken. Anything beyond that
+// would need real dataflow analysis and is left alone.
+func findLocals(pass *analysis.Pass) localValues {
+	if pass.TypesInfo == nil {
+		return nil
+	}
+	values := make(localValues)
+	changed := make(map[*types.Var]bool)
+
+	// define records ident = value when ident declares a local string
+	define := func(ident *ast.Ident, value ast.Expr) {
+		v, ok := pass.TypesInfo.Defs[ident].(*types.Var)
+		if !ok || v.Parent() == nil || v.Parent() == v.Pkg().Scope() {
+			return
+		}
+		if basic, ok := v.Type().Underlying().(*types.Basic); !ok || basic.Info()&types.IsString == 0 {
+			return
+		}
+		values[v] = value
+	}
+
+	// change marks the variable referred to by expr, if any, as changed
+	change := func(expr ast.Expr) {
+		if ident, ok := ast.Unparen(expr).(*ast.Ident); ok {
+			if v, ok := pass.TypesInfo.Uses[ident].(*types.Var); ok {
+				changed[v] = true
+			}
+		}
+	}
+
+	for _, file := range pass.Files {
+		ast.Inspect(file, func(node ast.Node) bool {
+			switch n := node.(type) {
+			case *ast.AssignStmt:
+				if n.Tok == token.DEFINE && len(n.Lhs) == len(n.Rhs) {
+					for i, lhs := range n.Lhs {
+						if ident, ok := lhs.(*ast.Ident); ok {
+							define(ident, n.Rhs[i])
+						}
+					}
+				}
+				// := also assigns to variables declared earlier in the scope
+				for _, lhs := range n.Lhs {
+					change(lhs)
+				}
+			case *ast.ValueSpec:
+				if len(n.Names) == len(n.Values) {
+					for i, name := range n.Names {
+						define(name, n.Values[i])
+					}
+				}
+			case *ast.RangeStmt:
+				if n.Tok == token.ASSIGN {
+					change(n.Key)
+					change(n.Value)
+				}
+			case *ast.UnaryExpr:
+				if n.Op == token.AND {
+					change(n.X)
+				}
+			}
+			return true
+		})
+	}
+
+	for v := range changed {
+		delete(values, v)
+	}
+	return values
+}
+
+// resolve returns the value of expr when it names a local variable assigned
+// only once, and expr itself otherwise
+func (l localValues) resolve(pass *analysis.Pass, expr ast.Expr) ast.Expr {
+	ident, ok := ast.Unparen(expr).(*ast.Ident)
+	if !ok || l == nil {
+		return expr
+	}
+	if v, ok := pass.TypesInfo.Uses[ident].(*types.Var); ok {
+		if value, ok := l[v]; ok {
+			return value
+		}
+	}
+	return expr
+}
@@ -0,0 +1,172 @@
+package duperrormsg
+
+import (
+	"regexp"
+	"sort"
+	"strings"
+	"unicode"
+	"unicode/utf8"
+)
+
+// verbPlaceholder replaces every formatting verb in a format string. It can't
+// be written in a Go string literal by accident, so literal text such as the
+// "%x" in errors.New("byte %x") never collides with a normalized verb.
+const verbPlaceholder = "\x00VERB\x00"
+
+// wrapPlaceholder replaces %w, which wraps its operand unlike every other
+// verb, so "x: %w" and "x: %v" are kept apart.
+const wrapPlaceholder = "\x00WRAP\x00"
+
+// placeholder matches either placeholder in a normalized message
+var placeholder = regexp.MustCompile("\x00[A-Z]+\x00")
+
+// placeholderReplacer spells the placeholders as verbs again, %x for any
+// verb and %w for wrapping, for patterns written against messages
+var placeholderReplacer = strings.NewReplacer(verbPlaceholder, "%x", wrapPlaceholder, "%w")
+
+// displayVerbs returns a normalized message with its placeholders as verbs
+func displayVerbs(msg string) string {
+	return placeholderReplacer.Replace(msg)
+}
+
+// formatVerb matches format specifiers like %s, %d or %v with their flags,
+// width, precision and explicit argument indexes, as in %-10.4f or %[2]*d,
+// as well as an escaped %% so the percent sign it leaves isn't taken for the
+// start of a verb.
+var formatVerb = regexp.MustCompile(`%%|%[-+# 0]*(?:\[\d+\])?(?:\*|\d+)?(?:\.(?:\[\d+\])?(?:\*|\d+)?)?(?:\[\d+\])?[a-zA-Z]`)
+
+// hasVerbs reports whether the format string msg holds any verb, not
+// counting escaped %% signs
+func hasVerbs(msg string) bool {
+	for _, verb := range formatVerb.FindAllString(msg, -1) {
+		if verb != "%%" {
+			return true
+		}
+	}
+	return false
+}
+
+// quotedVerb matches a %s or %v verb wrapped in double quotes by hand
+var quotedVerb = regexp.MustCompile(`"%[sv]"`)
+
+// normalizeQuoteVerbs rewrites hand-quoted verbs like "%s" in the format
+// string msg as %q, which quotes its operand the same way for plain strings.
+func normalizeQuoteVerbs(msg string) string {
+	return quotedVerb.ReplaceAllString(msg, "%q")
+}
+
+// normalizeVerbs replaces %w in the format string msg with wrapPlaceholder
+// and every other verb with verbPlaceholder, leaving an escaped %% as is
+func normalizeVerbs(msg string) string {
+	return formatVerb.ReplaceAllStringFunc(msg, func(verb string) string {
+		if verb == "%%" {
+			return verb
+		}
+		if strings.HasSuffix(verb, "w") {
+			return wrapPlaceholder
+		}
+		return verbPlaceholder
+	})
+}
+
+// escapeReplacer maps the control characters written as escapes to a space
+var escapeReplacer = strings.NewReplacer("\t", " ", "\n", " ", "\r", " ")
+
+// normalizeEscapes replaces each tab, newline and carriage return in msg with
+// a single space, keeping the rest of its whitespace as is.
+func normalizeEscapes(msg string) string {
+	return escapeReplacer.Replace(msg)
+}
+
+// normalizeWhitespace trims msg and collapses each internal run of
+// whitespace, such as a tab or a doubled space, to a single space.
+func normalizeWhitespace(msg string) string {
+	return strings.Join(strings.Fields(msg), " ")
+}
+
+// punctuationReplacer maps common unicode punctuation, usually pasted in from
+// documents, to the ASCII characters typed by hand.
+var punctuationReplacer = strings.NewReplacer(
+	"“", `"`, // left double quote
+	"”", `"`, // right double quote
+	"„", `"`, // low double quote
+	"″", `"`, // double prime
+	"‘", "'", // left single quote
+	"’", "'", // right single quote
+	"‚", "'", // low single quote
+	"′", "'", // prime
+	"‒", "-", // figure dash
+	"–", "-", // en dash
+	"—", "-", // em dash
+	"―", "-", // horizontal bar
+	"−", "-", // minus sign
+	"…", "...", // ellipsis
+)
+
+// normalizePunctuation replaces unicode quotes, dashes and ellipses with
+// their ASCII equivalents.
+func normalizePunctuation(msg string) string {
+	return punctuationReplacer.Replace(msg)
+}
+
+// stripTrailingPunctuation removes a single period, colon or exclamation mark
+// ending msg, which Go style leaves off error strings anyway
+func stripTrailingPunctuation(msg string) string {
+	if strings.HasSuffix(msg, ".") || strings.HasSuffix(msg, ":") || strings.HasSuffix(msg, "!") {
+		return msg[:len(msg)-1]
+	}
+	return msg
+}
+
+// trailingParens matches a parenthesized segment ending a message, along with
+// the whitespace before it
+var trailingParens = regexp.MustCompile(`\s*\([^)]*\)$`)
+
+// stripTrailingParens removes a parenthesized segment ending msg, such as an
+// error code in "payment declined (code 402)", unless it's the whole message
+func stripTrailingParens(msg string) string {
+	if stripped := trailingParens.ReplaceAllString(msg, ""); stripped != "" {
+		return stripped
+	}
+	return msg
+}
+
+// foldCase lowercases msg, leaving any normalized verbs intact
+func foldCase(msg string) string {
+	var b strings.Builder
+	last := 0
+	for _, loc := range placeholder.FindAllStringIndex(msg, -1) {
+		b.WriteString(strings.ToLower(msg[last:loc[0]]))
+		b.WriteString(msg[loc[0]:loc[1]])
+		last = loc[1]
+	}
+	b.WriteString(strings.ToLower(msg[last:]))
+	return b.String()
+}
+
+// sortWords sorts the whitespace separated words of msg, so messages using
+// the same words in any order compare equal. Punctuation stays attached to
+// the word it's written against.
+func sortWords(msg string) string {
+	words := strings.Fields(msg)
+	sort.Strings(words)
+	return strings.Join(words, " ")
+}
+
+// hasText reports whether a normalized message has any letters outside of
+// its verbs, unlike pure wrappers such as "%w" or ": %s". Verbs left in plain
+// messages, as in errors.New(": %s"), don't count as text either.
+func hasText(msg string) bool {
+	msg = formatVerb.ReplaceAllString(placeholder.ReplaceAllString(msg, ""), "")
+	return strings.IndexFunc(msg, unicode.IsLetter) >= 0
+}
+
+// messageLength returns the number of runes in a normalized message, counting
+// each formatting verb as a single rune.
+func messageLength(msg string) int {
+	n := utf8.RuneCountInString(msg)
+	for _, verb := range placeholder.FindAllString(msg, -1) {
+		n -= utf8.RuneCountInString(verb) - 1
+	}
+	return n
+}
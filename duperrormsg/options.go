@@ -0,0 +1,532 @@
+package duperrormsg
+
+import (
+	"flag"
+	"fmt"
+	"log"
+	"regexp"
+	"sort"
+	"strconv"
+	"strings"
+)
+
+// Scopes within which duplicate messages are reported
+const (
+	ScopePackage  = "package"  // Anywhere in the package
+	ScopeFile     = "file"     // Within a single file
+	ScopeFunction = "function" // Within a single function body
+)
+
+// Options configures the checker returned by NewAnalyzer
+type Options struct {
+	// Scope limits where duplicates are looked for, ScopePackage when empty
+	Scope string
+
+	// KeepWhitespace compares whitespace byte for byte. By default leading and
+	// trailing whitespace is trimmed and internal runs collapse to one space.
+	KeepWhitespace bool
+
+	// NormalizeEscapes replaces every tab, newline and carriage return with
+	// one space, so "a\tb" and "a\nb" are grouped together. Whitespace
+	// normalization already does so, this keeps other whitespace exact when
+	// it's turned off with KeepWhitespace.
+	NormalizeEscapes bool
+
+	// CrossPackage also reports messages used by a directly imported package,
+	// which are shared between packages as analysis facts
+	CrossPackage bool
+
+	// NormalizePunct maps unicode quotes, dashes and ellipses to ASCII
+	NormalizePunct bool
+
+	// StripTrailingPunctuation removes a single trailing period, colon or
+	// exclamation mark before comparing messages, after whitespace is
+	// normalized, so "operation failed." matches "operation failed"
+	StripTrailingPunctuation bool
+
+	// StripTrailingParens removes a parenthesized segment ending a message
+	// before comparing, so "payment declined (code 402)" matches
+	// "payment declined (code 403)"
+	StripTrailingParens bool
+
+	// NormalizeQuoteVerbs treats a hand-quoted "%s" or "%v" in a format string
+	// as %q. This is a heuristic, %q also escapes the value it quotes.
+	NormalizeQuoteVerbs bool
+
+	// IgnoreCase compares messages case-insensitively. Diagnostics still show
+	// each message as written.
+	IgnoreCase bool
+
+	// IgnoreWordOrder sorts the words of each message before comparing, so
+	// "user %s org %s" and "org %s user %s" are grouped together. Only whole
+	// whitespace separated words move, and it can group messages that mean
+	// different things, so it's best used to look for candidates.
+	IgnoreWordOrder bool
+
+	// IncludeTextless checks messages without any letters once their verbs
+	// are removed, such as "%w" or ": %s", which are skipped otherwise
+	IncludeTextless bool
+
+	// MinLength skips messages shorter than this many runes once normalized,
+	// with each formatting verb counting as one rune. Zero checks every message.
+	MinLength int
+
+	// Threshold is how many times a message has to be used before it's
+	// reported, 2 when zero. Raising it leaves pairs, such as a happy path
+	// and its mirror image, alone.
+	Threshold int
+
+	// SeparateByKind only groups messages used by the same kind of construct,
+	// so a log line isn't reported as a duplicate of a returned error
+	SeparateByKind bool
+
+	// SkipIfAllInOneFunc leaves out duplicates whose occurrences are all in a
+	// single function, where repetition is usually intentional
+	SkipIfAllInOneFunc bool
+
+	// HighlightCopyPaste prefixes duplicates with "likely copy-paste:" when
+	// two of their occurrences are in the same function, which is rarely
+	// deliberate, unlike the same message in unrelated functions
+	HighlightCopyPaste bool
+
+	// DedupeWindow only groups occurrences in the same file within this many
+	// lines of another occurrence, likely copy-paste in one block. Zero
+	// compares occurrences anywhere.
+	DedupeWindow int
+
+	// CollapseConsecutive counts a run of the same message on consecutive
+	// lines of a file once, keeping its first occurrence, since such runs are
+	// usually emitted together by a code generator
+	CollapseConsecutive bool
+
+	// Similarity groups messages that are at most this many edits apart,
+	// such as "could not connect" and "couldn't connect". Zero only groups
+	// identical messages.
+	Similarity int
+
+	// MaxFuzzyMessages skips Similarity, with a warning, in packages with more
+	// distinct messages than this, since comparing them is quadratic at
+	// worst. Zero compares any number of messages.
+	MaxFuzzyMessages int
+
+	// ReportAtLiteral reports each occurrence at the start of its message,
+	// such as the opening quote of a literal, instead of the call or the
+	// sentinel variable
+	ReportAtLiteral bool
+
+	// ReportEveryGroup reports every duplicate group, even at a position
+	// already holding a diagnostic of the same category. By default only the
+	// first is kept, as when two different messages are constructed by calls
+	// chained at the same position.
+	ReportEveryGroup bool
+
+	// LimitPerMessage caps how many other occurrences are listed with each
+	// duplicate, noting how many more were left out. Zero lists them all.
+	LimitPerMessage int
+
+	// IncludeTests checks messages in _test.go files, which are skipped otherwise
+	IncludeTests bool
+
+	// IgnoreTestHelpers skips messages inside functions whose names end in
+	// one of HelperSuffixes, which often repeat messages on purpose
+	IgnoreTestHelpers bool
+
+	// HelperSuffixes are the function name suffixes marking helpers, "Helper"
+	// and "Helpers" when empty
+	HelperSuffixes []string
+
+	// IncludeGenerated checks messages in files marked with a "// Code
+	// generated ... DO NOT EDIT." comment, which are skipped otherwise
+	IncludeGenerated bool
+
+	// ExcludePaths skips files whose path matches any of these patterns,
+	// "**/vendor/**" when nil. Patterns are matched against the whole cleaned,
+	// slash separated file name, segment by segment with path.Match, and a
+	// "**" segment matches any number of segments. As file names are
+	// absolute, patterns usually start with "**/".
+	ExcludePaths []string
+
+	// IncludeStringer checks files generated by stringer, which are skipped
+	// otherwise even when generated files are checked. Files that declare the
+	// _T_name and _T_index tables it emits count too, when edited by hand.
+	IncludeStringer bool
+
+	// Constructors registers additional functions or methods as error
+	// constructors. Each maps to the index of the argument holding the
+	// message, e.g. {"assertNoError": 2} for assertNoError(t, err, "msg").
+	// Names are bare, or qualified as "import/path.Func" or
+	// "import/path.Type.Method" to match a single package.
+	Constructors map[string]int
+
+	// IncludeTestify checks the substrings passed to testify's assert and
+	// require ErrorContains, grouped apart from the messages they match.
+	// Since they're found in tests, IncludeTests is needed too.
+	IncludeTestify bool
+
+	// IncludeHTTP treats http.Error as an error construct and folds
+	// http.StatusText of constant codes into its status text
+	IncludeHTTP bool
+
+	// Allowlist holds messages that are never reported. Entries are
+	// normalized like any other message, so "user %s gone" also covers
+	// fmt.Errorf("user %v gone", ...).
+	Allowlist []string
+
+	// IgnorePattern skips messages matching this regular expression, such as
+	// "^deprecated:". It's matched against the normalized message, where
+	// every formatting verb reads %x, except %w which is kept.
+	IgnorePattern string
+
+	// Equivalences are groups of messages treated as identical, such as "not
+	// found" and "does not exist". Members are normalized like any other
+	// message and grouped under the first message of their group.
+	Equivalences [][]string
+
+	// PublicAPIOnly limits checking to errors returned directly by exported
+	// functions and methods, the messages a package's users depend on
+	PublicAPIOnly bool
+
+	// ExportedOnly limits checking to errors assigned to exported package
+	// level variables or constructed anywhere in exported functions and
+	// methods, leaving out internal errors. Unlike PublicAPIOnly, errors
+	// needn't be returned directly.
+	ExportedOnly bool
+
+	// OnlyFormatStrings limits checking to printf-style constructs such as
+	// fmt.Errorf and Logf, ignoring plain messages like errors.New
+	OnlyFormatStrings bool
+
+	// SplitOnColon also groups messages by what follows their last ": ",
+	// reporting tails shared by messages with different prefixes, as in
+	// "reading config: file not found" and "reading secrets: file not found",
+	// apart from exact duplicates
+	SplitOnColon bool
+
+	// ReportSingletons also reports every message used fewer times than the
+	// threshold, usually once, so editors can list a package's inventory of
+	// messages. These diagnostics are informational.
+	ReportSingletons bool
+
+	// FlagStdlibCollisions reports every error constructed with the text of a
+	// common standard library error, such as "EOF" for io.EOF, which is
+	// usually meant to be returned, wrapped or compared against instead
+	FlagStdlibCollisions bool
+
+	// Summary also reports the number of duplicated messages and of their
+	// occurrences in the package, at the package clause of its first file
+	Summary bool
+
+	// Verbose logs every message found, and why any were skipped
+	Verbose bool
+
+	// DebugLogger receives verbose output and warnings, which go to stderr
+	// when nil
+	DebugLogger *log.Logger
+
+	// Detectors recognize additional error constructs in code, such as an
+	// ORM's error helper. They're consulted in order after the built-ins.
+	Detectors []ConstructDetector
+
+	// Workers bounds how many goroutines extract messages from a package,
+	// GOMAXPROCS when zero. Detectors must be safe for concurrent use.
+	Workers int
+
+	// Transforms are applied in order to every extracted message after the
+	// stages enabled by the other options. The final string is the key
+	// duplicates are grouped on.
+	Transforms []func(string) string
+
+	// pipeline caches transforms on the copy made by withPipeline
+	pipeline []func(string) string
+}
+
+func (o *Options) registerFlags(fs *flag.FlagSet) {
+	fs.StringVar(&o.Scope, "scope", o.Scope, "report duplicates within the whole package, a single file or a single function: package, file or function")
+	fs.Var((*negatedBool)(&o.KeepWhitespace), "normalize-whitespace", "trim whitespace and collapse internal runs to a single space before comparing messages")
+	fs.BoolVar(&o.NormalizeEscapes, "normalize-escapes", o.NormalizeEscapes, "replace each tab, newline and carriage return with a space before comparing messages")
+	fs.BoolVar(&o.NormalizePunct, "normalize-punct", o.NormalizePunct, "map unicode quotes, dashes and ellipses to ASCII before comparing messages")
+	fs.BoolVar(&o.StripTrailingPunctuation, "strip-trailing-punctuation", o.StripTrailingPunctuation, "ignore a single trailing period, colon or exclamation mark when comparing messages")
+	fs.BoolVar(&o.StripTrailingParens, "strip-trailing-parens", o.StripTrailingParens, "ignore a parenthesized segment ending a message, such as an error code, when comparing messages")
+	fs.BoolVar(&o.NormalizeQuoteVerbs, "normalize-quote-verbs", o.NormalizeQuoteVerbs, "heuristically treat a quoted \"%s\" or \"%v\" in format strings as %q")
+	fs.BoolVar(&o.IgnoreCase, "ignore-case", o.IgnoreCase, "compare messages case-insensitively")
+	fs.BoolVar(&o.IgnoreWordOrder, "ignore-word-order", o.IgnoreWordOrder, "compare messages regardless of the order of their words")
+	fs.Var((*negatedBool)(&o.IncludeTextless), "require-text", "skip messages without any letters outside their verbs, such as \"%w\" or \": %s\"")
+	fs.IntVar(&o.MinLength, "min-length", o.MinLength, "skip messages shorter than this many runes (not bytes) after normalization")
+	fs.IntVar(&o.Threshold, "threshold", o.Threshold, "only report messages used at least this many times, 2 or more")
+	fs.BoolVar(&o.SeparateByKind, "separate-by-kind", o.SeparateByKind, "only group messages used by the same kind of construct: error, log or panic")
+	fs.BoolVar(&o.SkipIfAllInOneFunc, "skip-if-all-in-one-func", o.SkipIfAllInOneFunc, "don't report duplicates whose occurrences are all in one function")
+	fs.BoolVar(&o.HighlightCopyPaste, "highlight-copy-paste", o.HighlightCopyPaste, "prefix duplicates used twice within one function with \"likely copy-paste:\"")
+	fs.IntVar(&o.DedupeWindow, "dedupe-window", o.DedupeWindow, "only group occurrences within N lines of another in the same file, 0 for anywhere")
+	fs.BoolVar(&o.CollapseConsecutive, "collapse-consecutive", o.CollapseConsecutive, "count runs of the same message on consecutive lines of a file as one occurrence")
+	fs.IntVar(&o.Similarity, "similarity", o.Similarity, "also group messages within this Levenshtein distance of each other, 0 for exact matches only")
+	fs.IntVar(&o.MaxFuzzyMessages, "max-fuzzy-messages", o.MaxFuzzyMessages, "skip -similarity in packages with more distinct messages than this, 0 for no limit")
+	fs.BoolVar(&o.ReportAtLiteral, "report-at-literal", o.ReportAtLiteral, "report occurrences at the message's opening quote instead of the call")
+	fs.Var((*negatedBool)(&o.ReportEveryGroup), "report-unique-once", "report at most one diagnostic of each category at any position")
+	fs.IntVar(&o.LimitPerMessage, "limit-per-message", o.LimitPerMessage, "list at most N other occurrences of each duplicate, 0 for all")
+	fs.BoolVar(&o.IncludeTests, "include-tests", o.IncludeTests, "check messages in _test.go files")
+	fs.BoolVar(&o.IgnoreTestHelpers, "ignore-test-helpers", o.IgnoreTestHelpers, "skip messages inside functions whose names end in one of -helper-suffixes")
+	fs.Var((*listFlag)(&o.HelperSuffixes), "helper-suffixes", "comma separated function name suffixes marking helpers for -ignore-test-helpers (default Helper,Helpers)")
+	fs.Var((*negatedBool)(&o.IncludeGenerated), "skip-generated", "skip files marked with a \"// Code generated ... DO NOT EDIT.\" comment")
+	fs.Var((*negatedBool)(&o.IncludeStringer), "skip-stringer", "skip files generated by stringer, or declaring its _T_name and _T_index tables")
+	fs.Var((*listFlag)(&o.ExcludePaths), "exclude-paths", "comma separated globs of files to skip, where ** matches any number of directories")
+	fs.Var((*constructorsFlag)(&o.Constructors), "constructors", "comma separated functions to treat as error constructors, as name or name@N where N is the message argument index; names may be qualified as import/path.Func")
+	fs.BoolVar(&o.IncludeTestify, "include-testify", o.IncludeTestify, "check expected error substrings passed to testify's assert and require ErrorContains, along with -include-tests")
+	fs.BoolVar(&o.IncludeHTTP, "include-http", o.IncludeHTTP, "check http.Error responses, resolving http.StatusText of constant codes")
+	fs.Var((*listFlag)(&o.Allowlist), "allowlist", "comma separated messages that are never reported, compared after normalization")
+	fs.StringVar(&o.IgnorePattern, "ignore-pattern", o.IgnorePattern, "skip messages matching this regexp, matched after normalization with verbs as %x and %w")
+	fs.Var((*equivalenceFlag)(&o.Equivalences), "equivalence", "file listing messages to treat as identical, one per line with groups separated by blank lines")
+	fs.BoolVar(&o.PublicAPIOnly, "public-api-only", o.PublicAPIOnly, "only check errors returned directly by exported functions and methods")
+	fs.BoolVar(&o.ExportedOnly, "exported-only", o.ExportedOnly, "only check errors assigned to exported variables or constructed in exported functions and methods")
+	fs.BoolVar(&o.OnlyFormatStrings, "only-format-strings", o.OnlyFormatStrings, "only check printf-style constructs such as fmt.Errorf and Logf")
+	fs.BoolVar(&o.SplitOnColon, "split-on-colon", o.SplitOnColon, "also report the text after the last \": \" shared by differently prefixed messages")
+	fs.BoolVar(&o.ReportSingletons, "report-singletons", o.ReportSingletons, "also report messages that aren't duplicates, as informational inventory diagnostics")
+	fs.BoolVar(&o.FlagStdlibCollisions, "flag-stdlib-collisions", o.FlagStdlibCollisions, "report errors constructed with the text of a standard library error, such as \"EOF\" for io.EOF")
+	fs.BoolVar(&o.Summary, "summary", o.Summary, "also report the number of duplicate message groups and call sites in each package")
+	fs.BoolVar(&o.Verbose, "verbose", o.Verbose, "log every message found and why any were skipped")
+}
+
+// validate checks the options, including any set by flags
+func (o *Options) validate() error {
+	if o.Threshold < 2 {
+		return fmt.Errorf("invalid threshold %d, a message has to be used at least twice", o.Threshold)
+	}
+	if o.DedupeWindow < 0 {
+		return fmt.Errorf("invalid dedupe window %d", o.DedupeWindow)
+	}
+	if o.Similarity < 0 {
+		return fmt.Errorf("invalid similarity %d", o.Similarity)
+	}
+	if o.MaxFuzzyMessages < 0 {
+		return fmt.Errorf("invalid max fuzzy messages %d", o.MaxFuzzyMessages)
+	}
+	if o.LimitPerMessage < 0 {
+		return fmt.Errorf("invalid limit per message %d", o.LimitPerMessage)
+	}
+	for _, pattern := range o.ExcludePaths {
+		if err := validPathPattern(pattern); err != nil {
+			return err
+		}
+	}
+	switch o.Scope {
+	case "", ScopePackage, ScopeFile, ScopeFunction:
+	default:
+		return fmt.Errorf("unknown scope %q, expected %s, %s or %s", o.Scope, ScopePackage, ScopeFile, ScopeFunction)
+	}
+	return nil
+}
+
+// applyDefaults fills in the options left empty that don't default to zero
+func (o *Options) applyDefaults() {
+	if o.Scope == "" {
+		o.Scope = ScopePackage
+	}
+	if o.ExcludePaths == nil {
+		o.ExcludePaths = defaultExcludePaths
+	}
+	if o.Threshold == 0 {
+		o.Threshold = 2
+	}
+}
+
+// isHelper reports whether a function named name is a helper skipped by
+// IgnoreTestHelpers
+func (o *Options) isHelper(name string) bool {
+	suffixes := o.HelperSuffixes
+	if len(suffixes) == 0 {
+		suffixes = []string{"Helper", "Helpers"}
+	}
+	for _, suffix := range suffixes {
+		if strings.HasSuffix(name, suffix) {
+			return true
+		}
+	}
+	return false
+}
+
+// debugf writes verbose output when enabled
+func (o *Options) debugf(format string, args ...interface{}) {
+	if !o.Verbose {
+		return
+	}
+	if o.DebugLogger != nil {
+		o.DebugLogger.Printf(format, args...)
+		return
+	}
+	log.Printf(format, args...)
+}
+
+// warnf writes a warning whether or not verbose output is enabled
+func (o *Options) warnf(format string, args ...interface{}) {
+	if o.DebugLogger != nil {
+		o.DebugLogger.Printf(format, args...)
+		return
+	}
+	log.Printf(format, args...)
+}
+
+// transforms returns the normalization pipeline, built-in stages first
+func (o *Options) transforms() []func(string) string {
+	var stages []func(string) string
+	if o.NormalizeEscapes {
+		stages = append(stages, normalizeEscapes)
+	}
+	if !o.KeepWhitespace {
+		stages = append(stages, normalizeWhitespace)
+	}
+	if o.NormalizePunct {
+		stages = append(stages, normalizePunctuation)
+	}
+	if o.StripTrailingPunctuation {
+		stages = append(stages, stripTrailingPunctuation)
+	}
+	if o.StripTrailingParens {
+		stages = append(stages, stripTrailingParens)
+	}
+	if o.IgnoreCase {
+		stages = append(stages, foldCase)
+	}
+	if o.IgnoreWordOrder {
+		stages = append(stages, sortWords)
+	}
+	return append(stages, o.Transforms...)
+}
+
+// Normalize runs msg through every transform enabled in o, in order, and
+// returns the key it would be grouped under.
+func (o *Options) Normalize(msg string) string {
+	stages := o.pipeline
+	if stages == nil {
+		stages = o.transforms()
+	}
+	for _, transform := range stages {
+		msg = transform(msg)
+	}
+	return msg
+}
+
+// withPipeline returns a copy of o that builds its normalization pipeline
+// once, rather than for every message, entry or equivalence it normalizes.
+// The copy must not be changed afterwards, as the pipeline wouldn't follow.
+func (o *Options) withPipeline() *Options {
+	c := *o
+	c.pipeline = c.transforms()
+	return &c
+}
+
+// ignored compiles IgnorePattern, returning nil when it's empty
+func (o *Options) ignored() (*regexp.Regexp, error) {
+	if o.IgnorePattern == "" {
+		return nil, nil
+	}
+	re, err := regexp.Compile(o.IgnorePattern)
+	if err != nil {
+		return nil, fmt.Errorf("invalid ignore pattern: %v", err)
+	}
+	return re, nil
+}
+
+// allowed returns the normalized allowlist. Entries are added both as
+// written and with their formatting verbs normalized, as whether a message
+// is a format string depends on the construct it's passed to.
+func (o *Options) allowed() map[string]bool {
+	allowed := make(map[string]bool, len(o.Allowlist))
+	for _, msg := range o.Allowlist {
+		allowed[o.Normalize(msg)] = true
+		allowed[o.Normalize(normalizeVerbs(msg))] = true
+	}
+	return allowed
+}
+
+// negatedBool is a boolean flag that sets the inverse of its value, so an
+// option can default to false while its flag defaults to true
+type negatedBool bool
+
+func (b *negatedBool) String() string {
+	return strconv.FormatBool(!bool(*b))
+}
+
+func (b *negatedBool) Set(value string) error {
+	v, err := strconv.ParseBool(value)
+	if err != nil {
+		return err
+	}
+	*b = negatedBool(!v)
+	return nil
+}
+
+func (b *negatedBool) IsBoolFlag() bool { return true }
+
+// listFlag parses a comma separated list of strings
+type listFlag []string
+
+func (f *listFlag) String() string {
+	return strings.Join(*f, ",")
+}
+
+func (f *listFlag) Set(value string) error {
+	var entries []string
+	for _, entry := range strings.Split(value, ",") {
+		if entry = strings.TrimSpace(entry); entry != "" {
+			entries = append(entries, entry)
+		}
+	}
+	*f = entries
+	return nil
+}
+
+// constructorsFlag parses a comma separated list of name[@N] entries
+type constructorsFlag map[string]int
+
+func (f *constructorsFlag) String() string {
+	var entries []string
+	for name, idx := range *f {
+		entries = append(entries, fmt.Sprintf("%s@%d", name, idx))
+	}
+	sort.Strings(entries)
+	return strings.Join(entries, ",")
+}
+
+func (f *constructorsFlag) Set(value string) error {
+	constructors := make(map[string]int)
+	for _, entry := range strings.Split(value, ",") {
+		entry = strings.TrimSpace(entry)
+		if entry == "" {
+			continue
+		}
+		name, index, found := strings.Cut(entry, "@")
+		idx := 0
+		if found {
+			n, err := strconv.Atoi(index)
+			if err != nil || n < 0 {
+				return fmt.Errorf("invalid message index in constructor %q", entry)
+			}
+			idx = n
+		}
+		if name == "" {
+			return fmt.Errorf("missing name in constructor %q", entry)
+		}
+		constructors[name] = idx
+	}
+	*f = constructors
+	return nil
+}
+
+// equivalenceFlag loads equivalence groups from the file it's set to
+type equivalenceFlag [][]string
+
+func (f *equivalenceFlag) String() string {
+	return ""
+}
+
+func (f *equivalenceFlag) Set(value string) error {
+	if value == "" {
+		*f = nil
+		return nil
+	}
+	groups, err := loadEquivalences(value)
+	if err != nil {
+		return err
+	}
+	*f = groups
+	return nil
+}
@@ -0,0 +1,71 @@
+package duperrormsg
+
+import (
+	"fmt"
+	"path"
+	"path/filepath"
+	"strings"
+
+	"golang.org/x/tools/go/analysis"
+)
+
+// defaultExcludePaths leaves out vendored dependencies
+var defaultExcludePaths = []string{"**/vendor/**"}
+
+// findExcluded returns the names of files matching any of patterns
+func findExcluded(pass *analysis.Pass, patterns []string) map[string]bool {
+	out := make(map[string]bool)
+	if len(patterns) == 0 {
+		return out
+	}
+	for _, file := range pass.Files {
+		name := pass.Fset.Position(file.Pos()).Filename
+		for _, pattern := range patterns {
+			if matchPath(pattern, name) {
+				out[name] = true
+				break
+			}
+		}
+	}
+	return out
+}
+
+// matchPath reports whether the cleaned, slash separated form of name
+// matches pattern. Both are split into segments on "/", a "**" segment
+// matches any number of segments, including none, and every other segment
+// is matched against a single segment with path.Match.
+func matchPath(pattern, name string) bool {
+	name = filepath.ToSlash(filepath.Clean(name))
+	return matchSegments(strings.Split(pattern, "/"), strings.Split(name, "/"))
+}
+
+func matchSegments(pattern, name []string) bool {
+	for len(pattern) > 0 {
+		if pattern[0] == "**" {
+			for i := len(name); i >= 0; i-- {
+				if matchSegments(pattern[1:], name[i:]) {
+					return true
+				}
+			}
+			return false
+		}
+		if len(name) == 0 {
+			return false
+		}
+		if ok, _ := path.Match(pattern[0], name[0]); !ok {
+			return false
+		}
+		pattern, name = pattern[1:], name[1:]
+	}
+	return len(name) == 0
+}
+
+// validPathPattern returns an error when pattern can't be matched
+func validPathPattern(pattern string) error {
+	for _, segment := range strings.Split(pattern, "/") {
+		if _, err := path.Match(segment, ""); err != nil {
+			return fmt.Errorf("invalid exclude path %q: %v", pattern, err)
+		}
+	}
+	return nil
+}
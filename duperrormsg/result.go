@@ -0,0 +1,263 @@
+package duperrormsg
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"go/token"
+	"sort"
+
+	"golang.org/x/tools/go/analysis"
+)
+
+// Result is the exported result of the Analyzer for a single package.
+//
+// It records every distinct message found, including those only used once,
+// so drivers can render inventories in addition to duplicates.
+type Result struct {
+	Groups []*Group // Sorted by message, then position
+
+	findings []Finding
+
+	// Kept to build diagnostics from
+	pass      *analysis.Pass
+	keys      []groupKey
+	errorMap  map[groupKey][]ErrorInfo
+	spellings map[groupKey][]string // Of groups merged by similarity
+}
+
+// Group collects every occurrence of a single normalized message
+type Group struct {
+	Message     string       // Normalized message used to group occurrences
+	Text        string       // Message as written at the first occurrence
+	Occurrences []Occurrence // In source order
+
+	// Filters applied by IsDuplicate, as set by the options the group was
+	// found with
+	threshold int  // Two when zero
+	oneFunc   bool // All occurrences are in one function with SkipIfAllInOneFunc
+
+	// How the analyzer split the package's occurrences, which MergeGroups
+	// keeps apart
+	kind  string // Kind of construct with SeparateByKind, or for testify
+	local bool   // Within a file or function, or a cluster by DedupeWindow
+}
+
+// Occurrence is a single location where a message was constructed
+type Occurrence struct {
+	Pos       token.Position
+	Construct string
+	Text      string // Message as written, before normalization
+	Func      string // Enclosing function, empty at package scope
+	Var       string // Sentinel error variable initialized by the construct, if any
+}
+
+// IsDuplicate reports whether the message is reported as a duplicate, used
+// at least Threshold times and, with SkipIfAllInOneFunc, in more than one
+// function
+func (g *Group) IsDuplicate() bool {
+	threshold := g.threshold
+	if threshold == 0 {
+		threshold = 2
+	}
+	return len(g.Occurrences) >= threshold && !g.oneFunc
+}
+
+// Files returns the number of distinct files the occurrences span, telling
+// duplicates local to a file apart from those spread across the package.
+func (g *Group) Files() int {
+	positions := make([]token.Position, len(g.Occurrences))
+	for i, occ := range g.Occurrences {
+		positions[i] = occ.Pos
+	}
+	return distinctFiles(positions)
+}
+
+// CountLocations describes how many locations a message is used at, adding
+// how many files they span when there's more than one, as in
+// "3 locations in 2 files". Diagnostics and reports built from a Group share
+// it so they read the same.
+func CountLocations(locations, files int) string {
+	count := fmt.Sprintf("%d locations", locations)
+	if locations == 1 {
+		count = "1 location"
+	}
+	if files > 1 {
+		count += fmt.Sprintf(" in %d files", files)
+	}
+	return count
+}
+
+// GroupHash returns a stable identifier for the group, derived from its
+// normalized message and the kinds of constructs used. Unlike positions it
+// survives unrelated edits, so it suits baselines and deduplication across runs.
+func (g *Group) GroupHash() string {
+	seen := make(map[string]bool)
+	var constructs []string
+	for _, occ := range g.Occurrences {
+		if !seen[occ.Construct] {
+			seen[occ.Construct] = true
+			constructs = append(constructs, occ.Construct)
+		}
+	}
+	sort.Strings(constructs)
+
+	h := sha256.New()
+	h.Write([]byte(g.Message))
+	for _, construct := range constructs {
+		h.Write([]byte{0})
+		h.Write([]byte(construct))
+	}
+	return hex.EncodeToString(h.Sum(nil))
+}
+
+// Duplicates returns the groups reported as duplicates, see IsDuplicate
+func (r *Result) Duplicates() []*Group {
+	var out []*Group
+	for _, g := range r.Groups {
+		if g.IsDuplicate() {
+			out = append(out, g)
+		}
+	}
+	return out
+}
+
+// MergeGroups combines groups with the same normalized message, such as those
+// of the Results for every package in a module, so duplicates can be found
+// across packages that don't import each other. Occurrences are sorted by
+// position and the merged groups by message. The groups passed in are left
+// untouched.
+//
+// Groups the analyzer kept apart stay apart: those of different kinds of
+// constructs with SeparateByKind aren't merged, and neither are those limited
+// to a file, a function or a DedupeWindow cluster, which can't span packages.
+// Merged groups take the highest threshold, and are only within one function
+// when every group is within the same one.
+func MergeGroups(groups []*Group) []*Group {
+	type mergeKey struct {
+		msg  string
+		kind string
+	}
+	byKey := make(map[mergeKey]*Group)
+	var out []*Group
+	for _, g := range groups {
+		if g.local {
+			copied := *g
+			copied.Occurrences = append([]Occurrence(nil), g.Occurrences...)
+			out = append(out, &copied)
+			continue
+		}
+		key := mergeKey{msg: g.Message, kind: g.kind}
+		merged, ok := byKey[key]
+		if !ok {
+			merged = &Group{Message: g.Message, threshold: g.threshold, oneFunc: g.oneFunc, kind: g.kind}
+			byKey[key] = merged
+			out = append(out, merged)
+		} else {
+			merged.threshold = max(merged.threshold, g.threshold)
+			merged.oneFunc = merged.oneFunc && g.oneFunc && sameFunc(merged.Occurrences[0], g.Occurrences[0])
+		}
+		merged.Occurrences = append(merged.Occurrences, g.Occurrences...)
+	}
+
+	for _, g := range out {
+		sort.SliceStable(g.Occurrences, func(i, j int) bool {
+			a, b := g.Occurrences[i].Pos, g.Occurrences[j].Pos
+			if a.Filename != b.Filename {
+				return a.Filename < b.Filename
+			}
+			return a.Offset < b.Offset
+		})
+		g.Text = g.Occurrences[0].Text
+	}
+	sort.SliceStable(out, func(i, j int) bool {
+		return out[i].Message < out[j].Message
+	})
+	return out
+}
+
+// sameFunc reports whether two occurrences are in the same function, which
+// is declared in a single file
+func sameFunc(a, b Occurrence) bool {
+	return a.Func != "" && a.Func == b.Func && a.Pos.Filename == b.Pos.Filename
+}
+
+func newResult(pass *analysis.Pass, opts *Options, keys []groupKey, errorMap map[groupKey][]ErrorInfo, spellings map[groupKey][]string) *Result {
+	result := &Result{
+		Groups:    make([]*Group, 0, len(keys)),
+		pass:      pass,
+		keys:      keys,
+		errorMap:  errorMap,
+		spellings: spellings,
+	}
+	names := newFuncNamer(pass.Files)
+	for _, key := range keys {
+		locations := errorMap[key]
+		group := &Group{
+			Message:   key.msg,
+			Text:      locations[0].Text,
+			threshold: opts.Threshold,
+			oneFunc:   opts.SkipIfAllInOneFunc && inOneFunc(locations),
+			kind:      key.kind,
+			local:     opts.Scope != "" && opts.Scope != ScopePackage || opts.DedupeWindow > 0,
+		}
+		for _, loc := range locations {
+			occ := Occurrence{
+				Pos:       pass.Fset.Position(loc.Pos.Pos()),
+				Construct: loc.Construct,
+				Text:      loc.Text,
+				Func:      names.name(loc.Func),
+			}
+			if loc.Var != nil {
+				occ.Var = loc.Var.Name
+			}
+			group.Occurrences = append(group.Occurrences, occ)
+		}
+		result.Groups = append(result.Groups, group)
+	}
+	return result
+}
+
+// Finding is a reported duplicate in a form suited to structured output
+type Finding struct {
+	Message    string           `json:"message"`    // Diagnostic message
+	Construct  string           `json:"construct"`  // Construct used at the primary position
+	Pos        token.Position   `json:"pos"`        // Where the finding is reported
+	Duplicates []token.Position `json:"duplicates"` // Every other occurrence, in source order
+	Files      int              `json:"files"`      // Distinct files the occurrences span
+	Category   string           `json:"category"`   // Category of the diagnostic, such as CategoryDuplicate
+
+	// HasFormatVerbs reports whether any occurrence is a format string with
+	// verbs, which unlike plain messages isn't safe to extract into a single
+	// shared error value.
+	HasFormatVerbs bool `json:"hasFormatVerbs"`
+}
+
+// Findings returns the duplicates reported for the package analyzed by pass,
+// in the order they were reported. The pass must belong to an analyzer that
+// requires a duplicate checker, such as a wrapper marshaling them to JSON.
+func Findings(pass *analysis.Pass) []Finding {
+	for _, res := range pass.ResultOf {
+		if r, ok := res.(*Result); ok {
+			return r.findings
+		}
+	}
+	return nil
+}
+
+func newFinding(pass *analysis.Pass, diag analysis.Diagnostic, locations []ErrorInfo) Finding {
+	finding := Finding{
+		Message:   diag.Message,
+		Category:  diag.Category,
+		Construct: locations[0].Construct,
+		Pos:       pass.Fset.Position(locations[0].reportPos()),
+		Files:     fileCount(pass.Fset, locations),
+	}
+	for _, loc := range locations[1:] {
+		finding.Duplicates = append(finding.Duplicates, pass.Fset.Position(loc.reportPos()))
+	}
+	for _, loc := range locations {
+		finding.HasFormatVerbs = finding.HasFormatVerbs || loc.HasFormatVerbs
+	}
+	return finding
+}
@@ -0,0 +1,167 @@
+package duperrormsg
+
+import (
+	"go/token"
+	"sort"
+	"unicode/utf8"
+)
+
+// mergeSimilar combines groups within the same scope whose messages are at
+// most distance edits apart. keys must be sorted, and each merged group takes
+// the place of its earliest key. The spellings of every merged group, as
+// written at the first occurrence of each message, are returned keyed by the
+// surviving key.
+//
+// Matches aren't chained: each earliest key not yet merged into another
+// represents a group, and only messages within distance edits of its own
+// message join it. So "bad token", "bad tokens" and "bad tokens!!" aren't
+// one group at a distance of 2, as the first and last are 3 edits apart.
+//
+// Messages are bucketed by length, since two messages can only be within
+// distance edits when their lengths differ by no more than that.
+func mergeSimilar(fset *token.FileSet, keys []groupKey, errorMap map[groupKey][]ErrorInfo, distance int) ([]groupKey, map[groupKey][]string) {
+	buckets := make(map[int][]int)
+	for i, key := range keys {
+		n := utf8.RuneCountInString(key.msg)
+		buckets[n] = append(buckets[n], i)
+	}
+
+	// Each key's representative, itself for those representing a group
+	rep := make([]int, len(keys))
+	for i := range rep {
+		rep[i] = -1
+	}
+
+	for i, key := range keys {
+		if rep[i] >= 0 {
+			continue
+		}
+		rep[i] = i
+		n := utf8.RuneCountInString(key.msg)
+		for length := n - distance; length <= n+distance; length++ {
+			for _, j := range buckets[length] {
+				if j <= i || rep[j] >= 0 || keys[j].scope != key.scope || keys[j].kind != key.kind {
+					continue
+				}
+				if levenshtein(key.msg, keys[j].msg) <= distance {
+					rep[j] = i
+				}
+			}
+		}
+	}
+
+	merged := keys[:0:0]
+	spellings := make(map[groupKey][]string)
+	for i, key := range keys {
+		root := keys[rep[i]]
+		if root == key {
+			merged = append(merged, key)
+			continue
+		}
+		if spellings[root] == nil {
+			spellings[root] = []string{errorMap[root][0].Text}
+		}
+		spellings[root] = append(spellings[root], errorMap[key][0].Text)
+		errorMap[root] = append(errorMap[root], errorMap[key]...)
+		delete(errorMap, key)
+	}
+	for root := range spellings {
+		locations := errorMap[root]
+		sort.Slice(locations, func(i, j int) bool {
+			return posLess(fset, locations[i].Pos.Pos(), locations[j].Pos.Pos())
+		})
+	}
+	return merged, spellings
+}
+
+// levenshtein returns the number of single rune insertions, deletions and
+// substitutions needed to turn a into b.
+func levenshtein(a, b string) int {
+	ra, rb := []rune(a), []rune(b)
+	prev := make([]int, len(rb)+1)
+	curr := make([]int, len(rb)+1)
+	for j := range prev {
+		prev[j] = j
+	}
+	for i := 1; i <= len(ra); i++ {
+		curr[0] = i
+		for j := 1; j <= len(rb); j++ {
+			cost := 1
+			if ra[i-1] == rb[j-1] {
+				cost = 0
+			}
+			curr[j] = min(prev[j]+1, curr[j-1]+1, prev[j-1]+cost)
+		}
+		prev, curr = curr, prev
+	}
+	return prev[len(rb)]
+}
+
+// splitByWindow breaks each group into clusters of occurrences in the same
+// file, each within window lines of the previous one, so only duplicates that
+// are physically close are reported. Clusters of a group merged by similarity
+// get the spellings of their own occurrences, as first written in the cluster.
+// keys must be sorted, and stay so.
+func splitByWindow(fset *token.FileSet, keys []groupKey, errorMap map[groupKey][]ErrorInfo, spellings map[groupKey][]string, window int) []groupKey {
+	var out []groupKey
+	for _, key := range keys {
+		locations := errorMap[key]
+		delete(errorMap, key)
+
+		cluster := key
+		var last token.Position
+		for i, loc := range locations {
+			pos := fset.Position(loc.Pos.Pos())
+			if i > 0 && (pos.Filename != last.Filename || pos.Line-last.Line > window) {
+				cluster.cluster++
+			}
+			last = pos
+			if _, ok := errorMap[cluster]; !ok {
+				out = append(out, cluster)
+			}
+			errorMap[cluster] = append(errorMap[cluster], loc)
+		}
+
+		if _, ok := spellings[key]; !ok {
+			continue
+		}
+		delete(spellings, key)
+		for c := key; c.cluster <= cluster.cluster; c.cluster++ {
+			if s := clusterSpellings(errorMap[c]); len(s) > 1 {
+				spellings[c] = s
+			}
+		}
+	}
+	return out
+}
+
+// clusterSpellings returns the text of the first occurrence of each distinct
+// message among locations
+func clusterSpellings(locations []ErrorInfo) []string {
+	seen := make(map[string]bool)
+	var out []string
+	for _, loc := range locations {
+		if !seen[loc.msg] {
+			seen[loc.msg] = true
+			out = append(out, loc.Text)
+		}
+	}
+	return out
+}
+
+// collapseConsecutive keeps only the first occurrence of each run of the
+// same message on consecutive lines of a file, as code generators emit when
+// unrolling a template, so the run counts once. locations must be sorted.
+func collapseConsecutive(fset *token.FileSet, locations []ErrorInfo) []ErrorInfo {
+	out := locations[:0:0]
+	var last token.Position
+	for i, loc := range locations {
+		pos := fset.Position(loc.Pos.Pos())
+		inRun := i > 0 && pos.Filename == last.Filename && pos.Line == last.Line+1
+		last = pos
+		if !inRun {
+			out = append(out, loc)
+		}
+	}
+	return out
+}
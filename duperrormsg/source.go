@@ -0,0 +1,68 @@
+package duperrormsg
+
+import (
+	"go/ast"
+	"go/importer"
+	"go/parser"
+	"go/token"
+	"go/types"
+
+	"golang.org/x/tools/go/analysis"
+	"golang.org/x/tools/go/analysis/passes/inspect"
+	"golang.org/x/tools/go/ast/inspector"
+)
+
+// CheckSource checks a single Go source file with the default options and
+// returns its duplicates, as the Analyzer would report them. See
+// Options.CheckSource.
+func CheckSource(filename string, src []byte) ([]Finding, error) {
+	return Options{}.CheckSource(filename, src)
+}
+
+// CheckSource parses src, type-checks it on its own and returns the
+// duplicates found, without a build-aware loader or analysis driver. Type
+// errors, such as imports that can't be found, are ignored and only leave
+// out the constructs recognized by their types. CrossPackage doesn't apply to
+// a single file. An error is returned when src doesn't parse or the options
+// are invalid.
+func (o Options) CheckSource(filename string, src []byte) ([]Finding, error) {
+	o.applyDefaults()
+
+	fset := token.NewFileSet()
+	file, err := parser.ParseFile(fset, filename, src, parser.ParseComments)
+	if err != nil {
+		return nil, err
+	}
+	files := []*ast.File{file}
+
+	info := &types.Info{
+		Types:      make(map[ast.Expr]types.TypeAndValue),
+		Defs:       make(map[*ast.Ident]types.Object),
+		Uses:       make(map[*ast.Ident]types.Object),
+		Implicits:  make(map[ast.Node]types.Object),
+		Selections: make(map[*ast.SelectorExpr]*types.Selection),
+		Scopes:     make(map[ast.Node]*types.Scope),
+	}
+	conf := types.Config{
+		Importer: importer.Default(),
+		Error:    func(error) {}, // Keep going with what can be resolved
+	}
+	pkg, _ := conf.Check(file.Name.Name, fset, files, info)
+
+	pass := &analysis.Pass{
+		Fset:      fset,
+		Files:     files,
+		Pkg:       pkg,
+		TypesInfo: info,
+		ResultOf: map[*analysis.Analyzer]interface{}{
+			inspect.Analyzer: inspector.New(files),
+		},
+		Report: func(analysis.Diagnostic) {},
+	}
+	result, err := analyze(pass, &o)
+	if err != nil {
+		return nil, err
+	}
+	_, findings := result.diagnose(&o)
+	return findings, nil
+}
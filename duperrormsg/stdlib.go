@@ -0,0 +1,52 @@
+package duperrormsg
+
+// stdlibErrors maps the text of common standard library errors to where
+// they come from. Messages repeating one are usually meant to return, wrap or
+// compare against the original error instead.
+var stdlibErrors = map[string]string{
+	// io
+	"EOF":                           "io.EOF",
+	"unexpected EOF":                "io.ErrUnexpectedEOF",
+	"short write":                   "io.ErrShortWrite",
+	"short buffer":                  "io.ErrShortBuffer",
+	"io: read/write on closed pipe": "io.ErrClosedPipe",
+
+	// context
+	"context canceled":          "context.Canceled",
+	"context deadline exceeded": "context.DeadlineExceeded",
+
+	// io/fs, also exported by os
+	"invalid argument":    "fs.ErrInvalid",
+	"permission denied":   "fs.ErrPermission",
+	"file already exists": "fs.ErrExist",
+	"file does not exist": "fs.ErrNotExist",
+	"file already closed": "fs.ErrClosed",
+	"i/o timeout":         "os.ErrDeadlineExceeded",
+
+	// net and net/http
+	"use of closed network connection": "net.ErrClosed",
+	"http: server closed":              "http.ErrServerClosed",
+	"http: named cookie not present":   "http.ErrNoCookie",
+	"http: request body too large":     "the error from http.MaxBytesReader",
+
+	// database/sql
+	"sql: no rows in result set":        "sql.ErrNoRows",
+	"sql: connection is already closed": "sql.ErrConnDone",
+
+	// Others
+	"unexpected end of JSON input":  "the *json.SyntaxError from encoding/json",
+	"bufio: buffer full":            "bufio.ErrBufferFull",
+	"bufio.Scanner: token too long": "bufio.ErrTooLong",
+	"invalid syntax":                "strconv.ErrSyntax",
+	"value out of range":            "strconv.ErrRange",
+}
+
+// stdlibCollisions returns the standard library errors keyed by their text
+// normalized with opts, so they compare like any other message.
+func (o *Options) stdlibCollisions() map[string]string {
+	out := make(map[string]string, len(stdlibErrors))
+	for text, source := range stdlibErrors {
+		out[o.Normalize(text)] = source
+	}
+	return out
+}
@@ -0,0 +1,21 @@
+package allowlist
+
+import (
+	"errors"
+	"fmt"
+)
+
+func reused(name string) {
+	errors.New("not implemented")
+	errors.New("not implemented")
+	errors.New("not implemented")
+
+	fmt.Errorf("user %s gone", name)
+	fmt.Errorf("user %v gone", name)
+}
+
+func reported() {
+	// Allowlist entries must match the whole message
+	errors.New("not implemented yet") // want "duplicate error message"
+	errors.New("not implemented yet")
+}
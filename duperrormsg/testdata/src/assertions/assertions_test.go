@@ -0,0 +1,43 @@
+package assertions
+
+import (
+	"testing"
+)
+
+func TestGet(t *testing.T) {
+	code := 404
+	if code != 200 {
+		t.Fatalf("expected 200 got %d", code) // want `duplicate error message "expected 200 got %d" used at 3 locations`
+	}
+	if code != 200 {
+		t.Errorf("expected 200 got %v", code)
+	}
+	t.Run("nested", func(tt *testing.T) {
+		tt.Fatalf("expected 200 got %d", code)
+	})
+}
+
+func BenchmarkGet(b *testing.B) {
+	b.Skipf("server %s unavailable", "a") // want `duplicate error message "server %s unavailable" used at 2 locations`
+	b.Logf("server %s unavailable", "b")
+}
+
+// Wrappers embedding *testing.T implement testing.TB too
+type suite struct {
+	*testing.T
+}
+
+func (s suite) check(got int) {
+	s.Logf("unexpected count %d", got) // want `duplicate error message "unexpected count %d" used at 2 locations`
+	s.Logf("unexpected count %d", got)
+}
+
+type recorder struct{}
+
+func (recorder) Logf(format string, args ...interface{}) {}
+
+func record(r recorder) {
+	// Other Logf methods aren't assertions
+	r.Logf("not an assertion")
+	r.Logf("not an assertion")
+}
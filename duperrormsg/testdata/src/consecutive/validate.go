@@ -0,0 +1,27 @@
+package consecutive
+
+import "errors"
+
+// Unrolled by a template, one check per field
+var (
+	errField0 = errors.New("field is required") // want `duplicate error message "field is required" used at 2 locations \(errors\.New\)$`
+	errField1 = errors.New("field is required")
+	errField2 = errors.New("field is required")
+)
+
+func validate(values []string) error {
+	switch {
+	case len(values) < 1:
+		return errors.New("too few values") // want `duplicate error message "too few values" used at 2 locations \(errors\.New\)$`
+	case len(values) > 8:
+		return errors.New("too many values")
+	}
+	return errors.New("too few values")
+}
+
+func lookup(name string) error {
+	if name == "" {
+		return errors.New("field is required")
+	}
+	return nil
+}
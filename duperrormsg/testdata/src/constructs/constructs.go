@@ -0,0 +1,23 @@
+package constructs
+
+import (
+	"errors"
+	"fmt"
+	"log"
+)
+
+func connect(addr string) error {
+	if addr == "" {
+		log.Printf("connection refused") // want `^duplicate error message "connection refused" used at 3 locations \(mixed constructs\)$`
+		return errors.New("connection refused")
+	}
+	if addr == "-" {
+		return fmt.Errorf("connection refused")
+	}
+
+	// A single construct is named
+	if addr == "*" {
+		return errors.New("address in use") // want `^duplicate error message "address in use" used at 2 locations \(errors\.New\)$`
+	}
+	return errors.New("address in use")
+}
@@ -0,0 +1,23 @@
+package controlchars
+
+import (
+	"errors"
+	"fmt"
+)
+
+func parse(line string) {
+	// Tab and newline separated fields are the same message
+	errors.New("missing field\tname") // want "duplicate error message"
+	errors.New("missing field\nname")
+	errors.New(`missing field
+name`)
+	fmt.Errorf("missing field\r%s", line) // want "duplicate error message"
+	fmt.Errorf("missing field\t%s", line)
+	fmt.Errorf("missing field\n%v", line)
+
+	// Other whitespace is still compared exactly
+	errors.New("invalid  header\tvalue")
+	errors.New("invalid header\tvalue")
+	errors.New("invalid\t\theader")
+	errors.New("invalid\theader")
+}
@@ -0,0 +1,40 @@
+package copypaste
+
+import (
+	"errors"
+	"fmt"
+)
+
+func read(n int) error {
+	// Twice within one function is called out
+	if n < 0 {
+		return fmt.Errorf("invalid length %d", n) // want `^likely copy-paste: duplicate error message "invalid length %d" used at 3 locations \(fmt\.Errorf\)$`
+	}
+	if n > 1<<20 {
+		return fmt.Errorf("invalid length %d", n)
+	}
+	return nil
+}
+
+func write(n int) error {
+	if n < 0 {
+		return fmt.Errorf("invalid length %d", n)
+	}
+
+	// Unrelated functions keep the usual wording
+	return errors.New("connection closed") // want `^duplicate error message "connection closed" used at 2 locations \(errors\.New\)$`
+}
+
+func flush() error {
+	return errors.New("connection closed")
+}
+
+func retry(attempts int) error {
+	// Function literals count as functions of their own
+	first := func() error { return errors.New("retry failed") } // want `^duplicate error message "retry failed" used at 2 locations \(errors\.New\)$`
+	second := func() error { return errors.New("retry failed") }
+	if attempts > 0 {
+		return first()
+	}
+	return second()
+}
@@ -0,0 +1,19 @@
+package api // want package:"3 messages"
+
+import (
+	"errors"
+	"fmt"
+
+	"crosspkg/store"
+)
+
+func handle(id string) error {
+	if err := store.Get(id); err != nil {
+		return fmt.Errorf("get %v: timed out", id) // want `error message "get %v: timed out" also used by imported package crosspkg/store at .*store.go:11:9`
+	}
+	return errors.New("record not found") // want `error message "record not found" also used by imported package crosspkg/store at .*store.go:8:5`
+}
+
+func render() error {
+	return errors.New("render failed")
+}
@@ -0,0 +1,12 @@
+package store
+
+import (
+	"errors"
+	"fmt"
+)
+
+var ErrNotFound = errors.New("record not found")
+
+func Get(id string) error {
+	return fmt.Errorf("get %s: timed out", id)
+}
@@ -0,0 +1,4 @@
+package catalog
+
+// New constructs an error, even though its import path holds "log"
+func New(msg string) error { return nil }
@@ -0,0 +1,30 @@
+package customctors
+
+import (
+	"context"
+
+	"customctors/problems"
+)
+
+func wrap(ctx context.Context, msg string) error { return nil }
+
+func problem(msg string) error { return nil }
+
+type validator struct{}
+
+// Report shares its name with problems.Report but isn't registered
+func (validator) Report(msg string) error { return nil }
+
+func validate(ctx context.Context, b *problems.Builder, id string) {
+	wrap(ctx, "invalid account") // want `duplicate error message "invalid account" used at 3 locations`
+	problem("invalid account")
+	problems.Report("invalid account")
+
+	b.Addf("id", "unknown id %s", id) // want `duplicate error message "unknown id %s" used at 2 locations`
+	b.Addf("id", "unknown id %v", id)
+
+	// Qualified names only match their own package
+	var v validator
+	v.Report("invalid user")
+	v.Report("invalid user")
+}
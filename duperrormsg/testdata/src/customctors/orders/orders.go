@@ -0,0 +1,14 @@
+package orders
+
+import (
+	"errors"
+
+	"customctors/catalog"
+)
+
+func lookup(sku string) error {
+	if sku == "" {
+		return catalog.New("item not found") // want `duplicate error message "item not found" used at 2 locations`
+	}
+	return errors.New("item not found")
+}
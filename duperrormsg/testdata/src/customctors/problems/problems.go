@@ -0,0 +1,12 @@
+package problems
+
+// Report returns a problem describing msg
+func Report(msg string) error {
+	return nil
+}
+
+// Builder collects problems
+type Builder struct{}
+
+// Addf records a formatted problem under field
+func (b *Builder) Addf(field, format string, args ...interface{}) {}
@@ -0,0 +1,25 @@
+package detectors
+
+import (
+	"errors"
+)
+
+type Store struct{}
+
+func (s *Store) Fault(table, format string, args ...interface{}) error {
+	return nil
+}
+
+func save(s *Store, id int) error {
+	if id == 0 {
+		return s.Fault("users", "row %d is locked", id) // want `duplicate error message "row %d is locked" used at 2 locations`
+	}
+	return s.Fault("accounts", "row %v is locked", id)
+}
+
+func load(s *Store) error {
+	// The table name isn't the message
+	s.Fault("users", "missing row")
+	s.Fault("users", "stale row")
+	return errors.New("users")
+}
@@ -0,0 +1,14 @@
+package dotimport
+
+import (
+	. "errors"
+)
+
+func open(name string) error {
+	// Registered constructors may be dot imported, which leaves no package to
+	// declare a shared variable with, so no fix is suggested
+	if name == "" {
+		return New("file not found") // want `duplicate error message "file not found" used at 2 locations`
+	}
+	return New("file not found")
+}
@@ -0,0 +1,23 @@
+package enclosure
+
+import (
+	"errors"
+	"fmt"
+)
+
+func startWorkers(jobs []string) {
+	for _, job := range jobs {
+		go func() error {
+			return fmt.Errorf("worker %s stalled", job) // want "duplicate error message"
+		}()
+	}
+
+	go func() error {
+		return fmt.Errorf("worker %v stalled", "last")
+	}()
+
+	retry := func() error {
+		return errors.New("retry budget exhausted")
+	}
+	_ = retry()
+}
@@ -0,0 +1,20 @@
+package equivalence
+
+import (
+	"errors"
+	"fmt"
+)
+
+func get(url string) {
+	// Synonyms listed together are one group, shown as first written
+	errors.New("Missing") // want `duplicate error message "Missing" used at 3 locations`
+	errors.New("not found")
+	errors.New("does  not exist")
+
+	fmt.Errorf("request to %s timed out", url) // want `duplicate error message "request to %s timed out" used at 2 locations`
+	fmt.Errorf("request to %q exceeded its deadline", url)
+
+	// Messages in no group are compared as usual
+	errors.New("gone")
+	errors.New("deleted")
+}
@@ -0,0 +1,27 @@
+package escapes
+
+import (
+	"errors"
+)
+
+// Raw and interpreted literals are compared by the text they denote
+func columns() {
+	errors.New(`name	value`) // want `duplicate error message "name\\tvalue" used at 2 locations`
+	errors.New("name\tvalue")
+
+	// A backslash in a raw string is literal text, not an escape
+	errors.New(`id\tkey`)
+	errors.New("id\tkey")
+}
+
+func lines() {
+	errors.New("line1\nline2") // want `duplicate error message "line1\\nline2" used at 3 locations`
+	errors.New(`line1
+line2`)
+	errors.New("line1" + `
+` + "line2")
+
+	// Escapes other than whitespace are decoded too
+	errors.New("caf\u00e9 closed") // want `duplicate error message "café closed" used at 2 locations`
+	errors.New(`café closed`)
+}
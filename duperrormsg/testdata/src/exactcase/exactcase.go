@@ -0,0 +1,11 @@
+package exactcase
+
+import (
+	"errors"
+)
+
+func open() {
+	// Casing matters unless -ignore-case is set
+	errors.New("File Not Found")
+	errors.New("file not found")
+}
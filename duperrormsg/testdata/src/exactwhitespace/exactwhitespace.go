@@ -0,0 +1,15 @@
+package exactwhitespace
+
+import (
+	"errors"
+	"fmt"
+)
+
+func connect() {
+	errors.New("failed to connect")
+	fmt.Errorf("failed to connect ")
+	errors.New("failed  to connect")
+
+	errors.New("failed to reconnect") // want "duplicate error message"
+	errors.New("failed to reconnect")
+}
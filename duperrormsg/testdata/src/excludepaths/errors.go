@@ -0,0 +1,15 @@
+package excludepaths
+
+import "errors"
+
+func Load() error {
+	return errors.New("load failed") // want `duplicate error message "load failed" used at 2 locations`
+}
+
+func Reload() error {
+	return errors.New("load failed")
+}
+
+func Save() error {
+	return errors.New("save failed")
+}
@@ -0,0 +1,18 @@
+package excludepaths
+
+import "errors"
+
+// Files matching -exclude-paths are never compared, even against the rest of
+// the package
+
+func LegacySave() error {
+	return errors.New("save failed")
+}
+
+func LegacyStore() error {
+	return errors.New("store failed")
+}
+
+func LegacyPersist() error {
+	return errors.New("store failed")
+}
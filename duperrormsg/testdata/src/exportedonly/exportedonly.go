@@ -0,0 +1,50 @@
+package exportedonly
+
+import (
+	"errors"
+	"fmt"
+)
+
+var ErrClosed = errors.New("connection closed") // want `duplicate error message "connection closed" used at 3 locations \(errors\.New\)$`
+
+var errIdle = errors.New("connection closed")
+
+type Client struct{}
+
+func Send(msg string) error {
+	if msg == "" {
+		// Errors needn't be returned directly, or outside function literals
+		err := errors.New("empty message") // want `duplicate error message "empty message" used at 2 locations \(errors\.New\)$`
+		return fmt.Errorf("send: %w", err)
+	}
+	return ErrClosed
+}
+
+func (c *Client) Write(msg string) error {
+	check := func() error {
+		if msg == "" {
+			return errors.New("empty message")
+		}
+		return errors.New("connection closed")
+	}
+	return check()
+}
+
+func (c *Client) Flush() error {
+	return errors.New("connection closed")
+}
+
+// The same messages in unexported functions, or methods of unexported types,
+// are internal and ignored
+func send(msg string) error {
+	if msg == "" {
+		return errors.New("empty message")
+	}
+	return errIdle
+}
+
+type conn struct{}
+
+func (c conn) Close() error {
+	return errors.New("connection closed")
+}
@@ -0,0 +1,10 @@
+package filescope
+
+import "fmt"
+
+func createAccount(owner string) error {
+	if owner == "" {
+		return fmt.Errorf("user %v already exists", owner)
+	}
+	return nil
+}
@@ -0,0 +1,19 @@
+package filescope
+
+import (
+	"errors"
+	"fmt"
+)
+
+func createUser(name string) error {
+	if name == "" {
+		return errors.New("missing name") // want `^duplicate error message "missing name" used at 2 locations \(errors\.New\)$`
+	}
+	if len(name) > 64 {
+		return errors.New("missing name")
+	}
+
+	// Used once here and once in the other file, which is shared vocabulary
+	// with -scope=file
+	return fmt.Errorf("user %s already exists", name)
+}
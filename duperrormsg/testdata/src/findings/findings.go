@@ -0,0 +1,14 @@
+package findings
+
+import (
+	"errors"
+	"fmt"
+)
+
+func write() {
+	errors.New("disk full")
+	fmt.Errorf("disk full")
+	errors.New("disk full")
+
+	errors.New("disk missing")
+}
@@ -0,0 +1,50 @@
+package fix
+
+import (
+	"errors"
+	"fmt"
+)
+
+var errDiskFull = fmt.Errorf("unrelated")
+
+func read() error {
+	if true {
+		return errors.New("connection failed") // want "duplicate error message"
+	}
+	return errors.New("connection failed")
+}
+
+func write() error {
+	if true {
+		return fmt.Errorf("disk full") // want "duplicate error message"
+	}
+	return errors.New("disk full")
+}
+
+func format(name string) error {
+	// Argument lists differ, so only the diagnostic is reported
+	if name == "" {
+		return fmt.Errorf("bad name %q", name) // want "duplicate error message"
+	}
+	return fmt.Errorf("bad name %q", name)
+}
+
+func banned(name string) error {
+	// The message depends on name, so it can't move to package level
+	if name == "" {
+		return errors.New(fmt.Sprintf("banned %q", "anonymous")) // want "duplicate error message"
+	}
+	return errors.New(fmt.Sprintf("banned %q", name))
+}
+
+func quota() error {
+	return errors.New("quota exceeded") // want "duplicate error message"
+}
+
+func lookup(errTimedOut error) error {
+	// A parameter with the generated name would shadow the variable
+	if errTimedOut != nil {
+		return errors.New("timed out") // want "duplicate error message"
+	}
+	return errors.New("timed out")
+}
@@ -0,0 +1,10 @@
+package fix
+
+import (
+	"errors"
+)
+
+// The fix would leave the errors import unused here, so none is offered
+func otherQuota() error {
+	return errors.New("quota exceeded")
+}
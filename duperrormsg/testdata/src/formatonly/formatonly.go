@@ -0,0 +1,29 @@
+package formatonly
+
+import (
+	"errors"
+	"fmt"
+	"log"
+)
+
+func plain() {
+	errors.New("not found")
+	errors.New("not found")
+
+	log.Print("retrying")
+	log.Print("retrying")
+}
+
+func formatted(name string) {
+	fmt.Errorf("user %s not found", name) // want "duplicate error message"
+	fmt.Errorf("user %v not found", name)
+
+	log.Printf("retrying %s", name) // want "duplicate error message"
+	log.Printf("retrying %s", name)
+}
+
+func mixed(name string) {
+	// Only the format-bearing occurrence is checked, so it has nothing to match
+	errors.New("connection reset")
+	fmt.Errorf("connection reset")
+}
@@ -0,0 +1,17 @@
+package generated
+
+import (
+	"errors"
+)
+
+// Only the hand-written occurrences are compared
+func read() error {
+	if true {
+		return errors.New("unexpected EOF")
+	}
+	return errors.New("short buffer") // want `duplicate error message "short buffer" used at 2 locations`
+}
+
+func write() error {
+	return errors.New("short buffer")
+}
@@ -0,0 +1,22 @@
+package glog
+
+// Error logs at the error level
+func Error(args ...interface{}) {}
+
+// Errorf logs a formatted message at the error level
+func Errorf(format string, args ...interface{}) {}
+
+// Warningf logs a formatted message at the warning level
+func Warningf(format string, args ...interface{}) {}
+
+// Fatal logs at the fatal level and exits
+func Fatal(args ...interface{}) {}
+
+// Fatalf logs a formatted message at the fatal level and exits
+func Fatalf(format string, args ...interface{}) {}
+
+// Exit logs at the fatal level and exits without a stack trace
+func Exit(args ...interface{}) {}
+
+// Exitf logs a formatted message like Exit
+func Exitf(format string, args ...interface{}) {}
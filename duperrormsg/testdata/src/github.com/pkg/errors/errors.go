@@ -0,0 +1,29 @@
+package errors
+
+import "fmt"
+
+// New returns an error with the supplied message
+func New(message string) error {
+	return fmt.Errorf("%s", message)
+}
+
+// Errorf formats according to a format specifier and returns the string as an error
+func Errorf(format string, args ...interface{}) error {
+	return fmt.Errorf(format, args...)
+}
+
+// Wrap returns an error annotating err with message
+func Wrap(err error, message string) error {
+	if err == nil {
+		return nil
+	}
+	return fmt.Errorf("%s: %w", message, err)
+}
+
+// Wrapf returns an error annotating err with the format specifier
+func Wrapf(err error, format string, args ...interface{}) error {
+	if err == nil {
+		return nil
+	}
+	return fmt.Errorf("%s: %w", fmt.Sprintf(format, args...), err)
+}
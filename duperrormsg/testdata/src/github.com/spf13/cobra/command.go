@@ -0,0 +1,24 @@
+package cobra
+
+import "fmt"
+
+// Command is a minimal stand-in for cobra's Command
+type Command struct {
+	Use  string
+	RunE func(cmd *Command, args []string) error
+}
+
+// PrintErr prints to stderr
+func (c *Command) PrintErr(i ...interface{}) {
+	fmt.Print(i...)
+}
+
+// PrintErrln prints to stderr with a trailing newline
+func (c *Command) PrintErrln(i ...interface{}) {
+	fmt.Println(i...)
+}
+
+// PrintErrf formats and prints to stderr
+func (c *Command) PrintErrf(format string, i ...interface{}) {
+	fmt.Printf(format, i...)
+}
@@ -0,0 +1,32 @@
+package assert
+
+import "strings"
+
+// TestingT is a minimal stand-in for testify's TestingT
+type TestingT interface {
+	Errorf(format string, args ...interface{})
+}
+
+// ErrorContains asserts that err contains the substring contains
+func ErrorContains(t TestingT, err error, contains string, msgAndArgs ...interface{}) bool {
+	if err == nil || !strings.Contains(err.Error(), contains) {
+		t.Errorf("error %v does not contain %q", err, contains)
+		return false
+	}
+	return true
+}
+
+// Assertions is a minimal stand-in for testify's Assertions
+type Assertions struct {
+	t TestingT
+}
+
+// New returns Assertions bound to t
+func New(t TestingT) *Assertions {
+	return &Assertions{t: t}
+}
+
+// ErrorContains asserts that err contains the substring contains
+func (a *Assertions) ErrorContains(err error, contains string, msgAndArgs ...interface{}) bool {
+	return ErrorContains(a.t, err, contains, msgAndArgs...)
+}
@@ -0,0 +1,31 @@
+package require
+
+import "github.com/stretchr/testify/assert"
+
+// TestingT is a minimal stand-in for testify's TestingT
+type TestingT interface {
+	Errorf(format string, args ...interface{})
+	FailNow()
+}
+
+// ErrorContains asserts that err contains the substring contains, stopping the test otherwise
+func ErrorContains(t TestingT, err error, contains string, msgAndArgs ...interface{}) {
+	if !assert.ErrorContains(t, err, contains, msgAndArgs...) {
+		t.FailNow()
+	}
+}
+
+// Assertions is a minimal stand-in for testify's require.Assertions
+type Assertions struct {
+	t TestingT
+}
+
+// New returns Assertions bound to t
+func New(t TestingT) *Assertions {
+	return &Assertions{t: t}
+}
+
+// ErrorContains asserts that err contains the substring contains, stopping the test otherwise
+func (a *Assertions) ErrorContains(err error, contains string, msgAndArgs ...interface{}) {
+	ErrorContains(a.t, err, contains, msgAndArgs...)
+}
@@ -0,0 +1,20 @@
+package glogerrors
+
+import (
+	"github.com/golang/glog"
+)
+
+func serve(addr string) {
+	// Format variants have their verbs normalized
+	glog.Errorf("listen %s failed", addr) // want `duplicate error message "listen %s failed" used at 3 locations`
+	glog.Warningf("listen %v failed", addr)
+	glog.Fatalf("listen %d failed", 80)
+
+	// Plain variants keep verbs as literal text
+	glog.Error("bind %s refused") // want `duplicate error message "bind %s refused" used at 2 locations`
+	glog.Fatal("bind %s refused")
+	glog.Error("bind %v refused")
+
+	glog.Exitf("shutdown %s", addr) // want `duplicate error message "shutdown %s" used at 2 locations`
+	glog.Exitf("shutdown %v", addr)
+}
@@ -0,0 +1,10 @@
+package codes
+
+// Code is a gRPC status code
+type Code uint32
+
+const (
+	OK       Code = 0
+	NotFound Code = 5
+	Internal Code = 13
+)
@@ -0,0 +1,17 @@
+package status
+
+import (
+	"fmt"
+
+	"google.golang.org/grpc/codes"
+)
+
+// Error returns an error representing c and msg
+func Error(c codes.Code, msg string) error {
+	return fmt.Errorf("rpc error: code = %d desc = %s", c, msg)
+}
+
+// Errorf returns an error representing c and a formatted message
+func Errorf(c codes.Code, format string, a ...interface{}) error {
+	return Error(c, fmt.Sprintf(format, a...))
+}
@@ -0,0 +1,31 @@
+package grpcerrors
+
+import (
+	"errors"
+
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+func getUser(id string) error {
+	if id == "" {
+		return status.Errorf(codes.NotFound, "user %s missing", id) // want `duplicate error message "user %s missing" used at 2 locations`
+	}
+	return status.Errorf(codes.Internal, "user %v missing", id)
+}
+
+func ping() error {
+	if true {
+		return status.Error(codes.Internal, "db unavailable") // want `duplicate error message "db unavailable" used at 3 locations`
+	}
+	if false {
+		return errors.New("db unavailable")
+	}
+	return status.Error(codes.Internal, "db unavailable")
+}
+
+func lookup(msg string) error {
+	// Only literal messages can be compared
+	status.Error(codes.NotFound, msg)
+	return status.Error(codes.NotFound, msg)
+}
@@ -0,0 +1,16 @@
+package hashes
+
+import (
+	"errors"
+	"fmt"
+)
+
+func write(path string) {
+	errors.New("disk full") // want `duplicate error message "disk full" used at 2 locations`
+	fmt.Errorf("disk full")
+
+	fmt.Errorf("open %s", path) // want `duplicate error message "open %s" used at 2 locations`
+	fmt.Errorf("open %v", path)
+
+	errors.New("open")
+}
@@ -0,0 +1,12 @@
+package helpers
+
+import (
+	"errors"
+)
+
+func LoadConfig(path string) error {
+	if path == "" {
+		return errors.New("empty config path")
+	}
+	return nil
+}
@@ -0,0 +1,32 @@
+package helpers
+
+import (
+	"testing"
+)
+
+func assertNoError(t *testing.T, err error, msg string) {
+	t.Helper()
+	if err != nil {
+		t.Fatalf("%s: %v", msg, err)
+	}
+}
+
+type suite struct {
+	name string
+}
+
+func (s suite) expectOK(t *testing.T, msg string, err error) {
+	t.Helper()
+	if err != nil {
+		t.Fatalf("%s %s: %v", s.name, msg, err)
+	}
+}
+
+func TestLoadConfig(t *testing.T) {
+	assertNoError(t, LoadConfig("a.yaml"), "loading config") // want "duplicate error message"
+	assertNoError(t, LoadConfig("b.yaml"), "loading config")
+
+	s := suite{name: "config"}
+	s.expectOK(t, "reloading config", LoadConfig("c.yaml")) // want "duplicate error message"
+	s.expectOK(t, "reloading config", LoadConfig("d.yaml"))
+}
@@ -0,0 +1,16 @@
+package helpersuffixes
+
+import (
+	"errors"
+)
+
+func userFixture() error {
+	errors.New("bad fixture")
+	return errors.New("bad fixture")
+}
+
+// Configured suffixes replace the defaults
+func assertHelper() error {
+	errors.New("assertion failed") // want `duplicate error message "assertion failed" used at 2 locations`
+	return errors.New("assertion failed")
+}
@@ -0,0 +1,21 @@
+package httperrors
+
+import (
+	"net/http"
+)
+
+func denied(w http.ResponseWriter, r *http.Request) {
+	http.Error(w, http.StatusText(http.StatusForbidden), http.StatusForbidden) // want "duplicate error message"
+}
+
+func locked(w http.ResponseWriter, r *http.Request) {
+	http.Error(w, "Forbidden", http.StatusForbidden)
+}
+
+func teapot(w http.ResponseWriter, r *http.Request, code int) {
+	// Only constant codes can be resolved
+	http.Error(w, http.StatusText(code), code)
+	http.Error(w, http.StatusText(code), code)
+
+	http.Error(w, "short and stout", http.StatusTeapot)
+}
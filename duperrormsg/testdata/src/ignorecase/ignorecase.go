@@ -0,0 +1,17 @@
+package ignorecase
+
+import (
+	"errors"
+	"fmt"
+)
+
+func open(name string) {
+	// The first occurrence is reported as written
+	errors.New("File Not Found") // want `duplicate error message "File Not Found" used at 3 locations`
+	errors.New("file not found")
+	errors.New("FILE NOT FOUND")
+
+	// Only the text around verbs is folded
+	fmt.Errorf("Open %s: Denied", name) // want `duplicate error message "Open %s: Denied" used at 2 locations`
+	fmt.Errorf("open %v: denied", name)
+}
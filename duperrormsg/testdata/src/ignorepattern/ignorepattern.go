@@ -0,0 +1,26 @@
+package ignorepattern
+
+import (
+	"errors"
+	"fmt"
+)
+
+func migrate(name string, err error) {
+	// Messages matching the pattern are exempt
+	errors.New("deprecated: use v2")
+	errors.New("deprecated: use v2")
+	errors.New("TEST fixture missing")
+	errors.New("TEST fixture missing")
+
+	// The pattern sees verbs as %x, and %w as itself
+	fmt.Errorf("scaffold %s not ready", name)
+	fmt.Errorf("scaffold %d not ready", 1)
+	fmt.Errorf("migrate %s: %w", name, err)
+	fmt.Errorf("migrate %v: %w", name, err)
+
+	// Everything else is still reported
+	errors.New("migration failed") // want "duplicate error message"
+	errors.New("migration failed")
+	fmt.Errorf("migrate %s: %v", name, err) // want "duplicate error message"
+	fmt.Errorf("migrate %q: %s", name, err)
+}
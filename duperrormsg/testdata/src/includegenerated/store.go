@@ -0,0 +1,9 @@
+package includegenerated
+
+import (
+	"errors"
+)
+
+func get() error {
+	return errors.New("record not found")
+}
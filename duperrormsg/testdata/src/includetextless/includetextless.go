@@ -0,0 +1,13 @@
+package includetextless
+
+import (
+	"errors"
+	"fmt"
+)
+
+func wrap(err error) {
+	fmt.Errorf("%w", err) // want `duplicate error message "%w" used at 2 locations`
+	fmt.Errorf("%w", err)
+	errors.New(": %s") // want `duplicate error message ": %s" used at 2 locations`
+	errors.New(": %s")
+}
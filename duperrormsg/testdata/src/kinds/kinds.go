@@ -0,0 +1,26 @@
+package kinds
+
+import (
+	"errors"
+	"fmt"
+	"log"
+)
+
+func get(key string) error {
+	// A log line and a returned error aren't the same thing
+	log.Printf("cache miss")
+	if key == "" {
+		panic("cache miss")
+	}
+	return errors.New("cache miss")
+}
+
+func put(key string) error {
+	// Errors built by different constructors are still the same kind
+	if key == "" {
+		return fmt.Errorf("cache full") // want `duplicate error message "cache full" used at 2 locations`
+	}
+	log.Print("evicting") // want `duplicate error message "evicting" used at 2 locations`
+	log.Println("evicting")
+	return errors.New("cache full")
+}
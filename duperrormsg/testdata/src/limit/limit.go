@@ -0,0 +1,19 @@
+package limit
+
+import (
+	"errors"
+)
+
+func validate() {
+	errors.New("invalid input") // want `duplicate error message "invalid input" used at 6 locations \(errors\.New\) \.\.\.and 3 more`
+	errors.New("invalid input")
+	errors.New("invalid input")
+	errors.New("invalid input")
+	errors.New("invalid input")
+	errors.New("invalid input")
+
+	// Groups within the limit are listed in full
+	errors.New("invalid state") // want `duplicate error message "invalid state" used at 3 locations \(errors\.New\)$`
+	errors.New("invalid state")
+	errors.New("invalid state")
+}
@@ -0,0 +1,25 @@
+package literalpos
+
+import (
+	"errors"
+	"fmt"
+	"log"
+)
+
+var ErrMiss = errors.New("cache miss") // want `duplicate error message "cache miss" used at 5 locations`
+
+func lookup(key string) error {
+	log.Printf("cache miss")
+	if key == "" {
+		return fmt.Errorf("cache miss")
+	}
+	if key == "*" {
+		// Folded concatenations are reported where the whole expression starts
+		return errors.New("cache" +
+			" " +
+			"miss")
+	}
+	return errors.New(
+		"cache miss",
+	)
+}
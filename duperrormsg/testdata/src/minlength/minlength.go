@@ -0,0 +1,33 @@
+package minlength
+
+import (
+	"errors"
+	"fmt"
+)
+
+func short() {
+	errors.New("eof")
+	errors.New("eof")
+
+	fmt.Errorf("n/a")
+	fmt.Errorf("n/a")
+
+	// Two runes of text plus a verb
+	fmt.Errorf("id %d", 1)
+	fmt.Errorf("id %v", 2)
+
+	// Four runes but eight bytes
+	errors.New("ключ")
+	errors.New("ключ")
+}
+
+func long() {
+	errors.New("timed out") // want "duplicate error message"
+	errors.New("timed out")
+
+	fmt.Errorf("key %s", "a") // want "duplicate error message"
+	fmt.Errorf("key %v", "b")
+
+	errors.New("ключи") // want "duplicate error message"
+	errors.New("ключи")
+}
@@ -0,0 +1,17 @@
+package multifile
+
+import (
+	"errors"
+	"fmt"
+)
+
+func open(name string) error {
+	if name == "" {
+		return errors.New("missing name") // want `duplicate error message "missing name" used at 3 locations in 2 files \(errors\.New\)$`
+	}
+	return fmt.Errorf("cannot open %s", name) // want `duplicate error message "cannot open %s" used at 2 locations \(fmt\.Errorf\)$`
+}
+
+func reopen(name string) error {
+	return fmt.Errorf("cannot open %s", name)
+}
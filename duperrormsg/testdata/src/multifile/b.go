@@ -0,0 +1,17 @@
+package multifile
+
+import "errors"
+
+func create(name string) error {
+	if name == "" {
+		return errors.New("missing name")
+	}
+	return nil
+}
+
+func remove(name string) error {
+	if name == "" {
+		return errors.New("missing name")
+	}
+	return nil
+}
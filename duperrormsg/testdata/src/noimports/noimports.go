@@ -0,0 +1,3 @@
+package noimports
+
+func noop() {}
@@ -0,0 +1,46 @@
+package nolint
+
+import (
+	"errors"
+)
+
+func suppressedFirst() {
+	errors.New("upstream unavailable") //nolint:duperror
+	errors.New("upstream unavailable") // want "duplicate error message"
+	errors.New("upstream unavailable")
+}
+
+func suppressedMiddle() {
+	errors.New("queue is full") // want "duplicate error message"
+	errors.New("queue is full") //nolint:duperror // retried below
+	errors.New("queue is full")
+}
+
+func suppressedPair() {
+	// Suppressing one of two occurrences leaves nothing to report
+	errors.New("lease expired")
+	errors.New("lease expired") //nolint:errcheck,duperror
+}
+
+func otherLinters() {
+	errors.New("bucket missing") //nolint:errcheck // want "duplicate error message"
+	errors.New("bucket missing")
+}
+
+func lintIgnore() {
+	// staticcheck style directives apply to the line below
+	//lint:ignore duperror the primary message, kept on purpose
+	errors.New("token revoked")
+	errors.New("token revoked") // want "duplicate error message"
+	//lint:ignore SA1019,duperror shared with the legacy client
+	errors.New("token revoked")
+	errors.New("token revoked")
+}
+
+func lintIgnoreOthers() {
+	//lint:ignore SA1019 other checks don't suppress anything
+	errors.New("scope denied") // want "duplicate error message"
+	// Nor do directives without a reason, which staticcheck rejects too
+	//lint:ignore duperror
+	errors.New("scope denied")
+}
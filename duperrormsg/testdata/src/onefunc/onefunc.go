@@ -0,0 +1,33 @@
+package onefunc
+
+import (
+	"errors"
+)
+
+var (
+	errA = errors.New("invalid header") // want `duplicate error message "invalid header" used by errA and errB`
+	errB = errors.New("invalid header")
+)
+
+// Repetition within a single function is left alone
+func parse(kind int) error {
+	switch kind {
+	case 0:
+		return errors.New("unsupported kind")
+	case 1:
+		return errors.New("unsupported kind")
+	}
+	go func() {
+		errors.New("unsupported kind")
+	}()
+	return nil
+}
+
+// Groups spread across functions are still reported
+func read() error {
+	return errors.New("short read") // want `duplicate error message "short read" used at 2 locations`
+}
+
+func write() error {
+	return errors.New("short read")
+}
@@ -0,0 +1,13 @@
+package pairs
+
+import "errors"
+
+func sync() {
+	// Pairs are reported by default
+	errors.New("sync skipped") // want `duplicate error message "sync skipped" used at 2 locations`
+	errors.New("sync skipped")
+
+	errors.New("sync failed") // want `duplicate error message "sync failed" used at 3 locations`
+	errors.New("sync failed")
+	errors.New("sync failed")
+}
@@ -0,0 +1,15 @@
+package pkgerrors
+
+import (
+	"github.com/pkg/errors"
+)
+
+func connect(err error, user string) {
+	// The message is the second argument of Wrap and Wrapf
+	errors.Wrap(err, "failed to connect") // want "duplicate error message"
+	errors.Wrapf(err, "failed to connect")
+	errors.Wrapf(err, "user %s failed", user) // want "duplicate error message"
+	errors.Errorf("user %v failed", user)
+	errors.New("connection refused") // want "duplicate error message"
+	errors.Wrap(errors.New("x"), "connection refused")
+}
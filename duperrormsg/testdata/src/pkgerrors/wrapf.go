@@ -0,0 +1,18 @@
+package pkgerrors
+
+import (
+	"fmt"
+
+	"github.com/pkg/errors"
+)
+
+func save(err error, name string) {
+	// The message is the first argument of fmt.Errorf but the second of Wrapf,
+	// and both are normalized as format strings
+	fmt.Errorf("save %s", name) // want `duplicate error message "save %s" used at 2 locations`
+	errors.Wrapf(err, "save %v", name)
+
+	// Wrap isn't a format construct, so its verb is literal text
+	fmt.Errorf("load %s", name)
+	errors.Wrap(err, "load %s")
+}
@@ -0,0 +1,45 @@
+package publicapi
+
+import (
+	"errors"
+	"fmt"
+)
+
+type Client struct{}
+
+func Open(name string) error {
+	if name == "" {
+		return errors.New("missing name") // want "duplicate error message"
+	}
+	return fmt.Errorf("open %s: %w", name, errors.New("not found"))
+}
+
+func (c *Client) Close() error {
+	return (errors.New("missing name"))
+}
+
+// Errors that aren't returned directly, or not from the public API, are ignored
+func (c *Client) reset() error {
+	return errors.New("missing name")
+}
+
+type conn struct{}
+
+func (c conn) Close() error {
+	return errors.New("missing name")
+}
+
+func Dial() error {
+	err := errors.New("not found")
+	retry := func() error {
+		return errors.New("missing name")
+	}
+	if err := retry(); err != nil {
+		return err
+	}
+	return err
+}
+
+func dial() error {
+	return errors.New("missing name")
+}
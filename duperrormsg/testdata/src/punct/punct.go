@@ -0,0 +1,22 @@
+package punct
+
+import (
+	"errors"
+)
+
+func quotes() {
+	errors.New("option “verbose” is unknown") // want "duplicate error message"
+	errors.New(`option "verbose" is unknown`)
+
+	errors.New("can’t open socket") // want "duplicate error message"
+	errors.New("can't open socket")
+}
+
+func dashes() {
+	errors.New("retry limit – giving up") // want "duplicate error message"
+	errors.New("retry limit — giving up")
+	errors.New("retry limit - giving up")
+
+	errors.New("still waiting…") // want "duplicate error message"
+	errors.New("still waiting...")
+}
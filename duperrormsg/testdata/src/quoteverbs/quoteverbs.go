@@ -0,0 +1,18 @@
+package quoteverbs
+
+import (
+	"errors"
+	"fmt"
+)
+
+func lookup(key string) {
+	fmt.Errorf("unknown key %q in config", key) // want `duplicate error message "unknown key %q in config" used at 3 locations`
+	fmt.Errorf(`unknown key "%s" in config`, key)
+	fmt.Errorf(`unknown key "%v" in config`, key)
+
+	// Quotes around other verbs, or in plain messages, are left alone
+	fmt.Errorf(`unknown id "%d"`, 1)
+	fmt.Errorf("unknown id %q", 1)
+	errors.New(`unknown name "%s"`)
+	errors.New("unknown name %q")
+}
@@ -0,0 +1,17 @@
+package samepos
+
+import "errors"
+
+type fault struct{}
+
+func NewErr(msg string) *fault { return &fault{} }
+
+func (f *fault) Log(msg string) {}
+
+func flush() {
+	// Both calls start at NewErr, yet only one diagnostic is reported there
+	NewErr("disk full").Log("disk is full") // want `duplicate error message "disk full" used at 2 locations \(mixed constructs\)$`
+
+	errors.New("disk full")
+	errors.New("disk is full")
+}
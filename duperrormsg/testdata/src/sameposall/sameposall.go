@@ -0,0 +1,17 @@
+package sameposall
+
+import "errors"
+
+type fault struct{}
+
+func NewErr(msg string) *fault { return &fault{} }
+
+func (f *fault) Log(msg string) {}
+
+func flush() {
+	// Both calls start at NewErr, and each group is reported there
+	NewErr("disk full").Log("disk is full") // want `duplicate error message "disk full"` `duplicate error message "disk is full"`
+
+	errors.New("disk full")
+	errors.New("disk is full")
+}
@@ -0,0 +1,69 @@
+package scope
+
+import (
+	"errors"
+	"fmt"
+)
+
+var errClosed = errors.New("store closed")
+
+func get(key string) error {
+	if key == "" {
+		return errors.New("empty key")
+	}
+	return errors.New("store closed")
+}
+
+func put(key, value string) error {
+	if key == "" {
+		return errors.New("empty key")
+	}
+	if value == "" {
+		return fmt.Errorf("empty value for %s", key) // want "duplicate error message"
+	}
+	if len(value) > 1024 {
+		return fmt.Errorf("empty value for %v", key)
+	}
+	return nil
+}
+
+func remove(keys []string) error {
+	check := func(key string) error {
+		// Closures are a function of their own
+		return errors.New("empty key")
+	}
+	for _, key := range keys {
+		if err := check(key); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func commit() (err error) {
+	defer func() {
+		if err != nil {
+			err = fmt.Errorf("closing transaction: %w", err)
+		}
+	}()
+	return nil
+}
+
+func rollback() (err error) {
+	defer func() {
+		if err != nil {
+			err = fmt.Errorf("closing transaction: %w", err)
+		}
+	}()
+	return nil
+}
+
+func flush() (err error) {
+	defer func() {
+		if err != nil {
+			// Attributed to flush, alongside the return below
+			err = fmt.Errorf("flushing buffer: %w", err) // want "duplicate error message"
+		}
+	}()
+	return fmt.Errorf("flushing buffer: %w", errClosed)
+}
@@ -0,0 +1,28 @@
+package similar
+
+import (
+	"errors"
+	"fmt"
+)
+
+func connect() {
+	errors.New("could not connect to database") // want `similar error messages "could not connect to database" and "couldn't connect to database" used at 3 locations`
+	errors.New("couldn't connect to database")
+	errors.New("could not connect to database")
+
+	// Exact duplicates are reported as before
+	fmt.Errorf("dial %s", "a") // want `duplicate error message "dial %s" used at 2 locations`
+	fmt.Errorf("dial %v", "b")
+
+	// Too far apart to be considered the same
+	errors.New("user not found")
+	errors.New("group not found")
+}
+
+func chained() {
+	// Matches aren't chained, each message must be within the distance of
+	// the first, so the last one, 6 edits away from it, is left out
+	errors.New("bad token") // want `^similar error messages "bad token" and "bad tokens!!" used at 2 locations \(errors\.New\)$`
+	errors.New("bad tokens!!")
+	errors.New("bad tokens!!!!!")
+}
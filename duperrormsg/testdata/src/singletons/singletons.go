@@ -0,0 +1,18 @@
+package singletons
+
+import (
+	"errors"
+	"fmt"
+	"log"
+)
+
+func sync(name string) error {
+	if name == "" {
+		return errors.New("missing name") // want `^duplicate error message "missing name" used at 2 locations \(errors\.New\)$`
+	}
+	if name == "-" {
+		return errors.New("missing name")
+	}
+	log.Printf("syncing %s", name)                    // want `^error message "syncing %s" used at 1 location$`
+	return fmt.Errorf("sync %s: not supported", name) // want `^error message "sync %s: not supported" used at 1 location$`
+}
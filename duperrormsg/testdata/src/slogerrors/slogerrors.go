@@ -0,0 +1,27 @@
+package slogerrors
+
+import (
+	"context"
+	"log/slog"
+)
+
+func flush(ctx context.Context, logger *slog.Logger, size int) {
+	// Package level functions and methods on a logger, whatever it's called
+	slog.Error("failed to flush buffer", "size", size) // want `duplicate error message "failed to flush buffer" used at 3 locations`
+	logger.Error("failed to flush buffer")
+	slog.Default().Error("failed to flush buffer", slog.Int("size", size))
+
+	// The Context forms take the message after the context
+	slog.WarnContext(ctx, "buffer nearly full", "size", size) // want `duplicate error message "buffer nearly full" used at 2 locations`
+	logger.InfoContext(ctx, "buffer nearly full")
+
+	// Log and LogAttrs take the message after the level
+	slog.Log(ctx, slog.LevelDebug, "buffer drained") // want `duplicate error message "buffer drained" used at 2 locations`
+	logger.LogAttrs(ctx, slog.LevelDebug, "buffer drained", slog.Int("size", size))
+
+	// Keys of key-value pairs aren't messages
+	slog.Info("flushed", "size", size)
+	slog.Debug("flushing", "size", size)
+	l := logger.With("size", size)
+	l.Debug("flush skipped")
+}
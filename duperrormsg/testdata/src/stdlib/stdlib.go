@@ -0,0 +1,22 @@
+package stdlib
+
+import (
+	"errors"
+	"fmt"
+	"log"
+)
+
+var ErrEOF = errors.New("EOF") // want `error message "EOF" matches the text of io\.EOF, return, wrap or compare against it instead`
+
+func read(buf []byte) error {
+	if len(buf) == 0 {
+		return fmt.Errorf("context deadline exceeded") // want `matches the text of context\.DeadlineExceeded`
+	}
+	if buf[0] == '{' {
+		return errors.New("unexpected end of JSON input") // want `matches the text of the \*json\.SyntaxError from encoding/json`
+	}
+
+	// Only errors are flagged, and wrapping the text is fine
+	log.Printf("context canceled")
+	return fmt.Errorf("EOF while reading header: %w", ErrEOF)
+}
@@ -0,0 +1,28 @@
+package stringer
+
+import (
+	"errors"
+	"strconv"
+)
+
+// Originally generated by stringer, since edited to add ParseColor
+
+const _Color_name = "RedGreenBlue"
+
+var _Color_index = [...]uint8{0, 3, 8, 12}
+
+func (i Color) String() string {
+	if i < 0 || i >= Color(len(_Color_index)-1) {
+		return "Color(" + strconv.FormatInt(int64(i), 10) + ")"
+	}
+	return _Color_name[_Color_index[i]:_Color_index[i+1]]
+}
+
+func ParseColor(s string) (Color, error) {
+	for i := 0; i < len(_Color_index)-1; i++ {
+		if s == _Color_name[_Color_index[i]:_Color_index[i+1]] {
+			return Color(i), nil
+		}
+	}
+	return 0, errors.New("unknown color")
+}
@@ -0,0 +1,32 @@
+package stringer
+
+import (
+	"errors"
+	"fmt"
+)
+
+type Kind int
+
+const (
+	Debit Kind = iota
+	Credit
+)
+
+type Color int
+
+const (
+	Red Color = iota
+	Green
+	Blue
+)
+
+func describe(k Kind) string {
+	return fmt.Sprintf("Kind(%d)", int(k))
+}
+
+func mix(a, b Color) (Color, error) {
+	if a == b {
+		return a, nil
+	}
+	return 0, errors.New("unknown color")
+}
@@ -0,0 +1,40 @@
+package summary // want `^found 2 duplicate message groups covering 5 call sites$`
+
+import (
+	"errors"
+	"fmt"
+)
+
+func sync(name string) error {
+	if name == "" {
+		return errors.New("missing name") // want `duplicate error message "missing name" used at 3 locations`
+	}
+	if name == "-" {
+		return errors.New("missing name")
+	}
+	if name == "*" {
+		return fmt.Errorf("sync %s failed", name) // want `duplicate error message "sync %s failed" used at 2 locations`
+	}
+	if name == "." {
+		return errors.New("missing name")
+	}
+	return fmt.Errorf("sync %q failed", name)
+}
+
+func unique() error {
+	return errors.New("not counted")
+}
+
+func load(path string) error {
+	// A tail shared with -split-on-colon, spanning messages counted on
+	// their own
+	if path == "" {
+		return errors.New("reading config: file not found")
+	}
+	return errors.New("reading secrets: file not found")
+}
+
+func read() error {
+	// Flagged with -flag-stdlib-collisions, but not a duplicate
+	return errors.New("EOF")
+}
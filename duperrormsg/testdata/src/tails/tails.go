@@ -0,0 +1,32 @@
+package tails
+
+import (
+	"errors"
+	"fmt"
+)
+
+func load(path string) error {
+	if path == "" {
+		return errors.New("reading config: file not found") // want `^duplicate error tail "file not found" across differently-prefixed messages$`
+	}
+	if path == "-" {
+		return errors.New("reading secrets: file not found")
+	}
+
+	// Exact duplicates are reported on their own, and their tail once
+	if path == "." {
+		return fmt.Errorf("opening %s: permission denied", path) // want `^duplicate error message "opening %s: permission denied" used at 2 locations \(fmt\.Errorf\)$` `^duplicate error tail "permission denied" across differently-prefixed messages$`
+	}
+	if path == ".." {
+		return fmt.Errorf("opening %v: permission denied", path)
+	}
+	if path == "/" {
+		return errors.New("writing cache: permission denied")
+	}
+
+	// A tail without text isn't a message of its own
+	if path == "~" {
+		return fmt.Errorf("reading config: %w", errors.ErrUnsupported)
+	}
+	return fmt.Errorf("reading secrets: %w", errors.ErrUnsupported)
+}
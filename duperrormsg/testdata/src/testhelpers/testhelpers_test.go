@@ -0,0 +1,29 @@
+package testhelpers
+
+import (
+	"errors"
+	"fmt"
+	"testing"
+)
+
+// Helpers repeat messages on purpose, and are skipped entirely
+func assertHelper(t *testing.T, err error) {
+	if err != nil {
+		t.Fatal(errors.New("unexpected error"))
+	}
+	func() {
+		t.Fatal(errors.New("unexpected error"))
+	}()
+}
+
+type checks struct{}
+
+func (checks) requestHelpers(t *testing.T) error {
+	return fmt.Errorf("unexpected error")
+}
+
+func TestLoad(t *testing.T) {
+	errors.New("load failed") // want `duplicate error message "load failed" used at 2 locations`
+	errors.New("load failed")
+	errors.New("unexpected error")
+}
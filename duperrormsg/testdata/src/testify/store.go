@@ -0,0 +1,10 @@
+package testify
+
+import "errors"
+
+func load(key string) error {
+	if key == "" {
+		return errors.New("empty key")
+	}
+	return errors.New("key not found")
+}
@@ -0,0 +1,27 @@
+package testify
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestLoad(t *testing.T) {
+	// Expected substrings repeated across tests are reported on their own,
+	// never as duplicates of the messages they match
+	assert.ErrorContains(t, load(""), "empty key") // want `duplicate expected error substring "empty key" used at 3 locations \(mixed constructs\)$`
+	require.ErrorContains(t, load("missing"), "key not found")
+}
+
+func TestLoadEmpty(t *testing.T) {
+	require.ErrorContains(t, load(""), "empty key", "loading %q", "")
+}
+
+func TestLoadAssertions(t *testing.T) {
+	is := assert.New(t)
+	is.ErrorContains(load(""), "empty key")
+
+	must := require.New(t)
+	must.ErrorContains(load("other"), "not found")
+}
@@ -0,0 +1,23 @@
+package tests
+
+import (
+	moovlog "github.com/moov-io/base/log"
+)
+
+func bufferLogger(id string) {
+	// The logger is recognized by its type rather than its name
+	_, l := moovlog.NewBufferLogger()
+
+	l.Logf("account %s not found", id) // want "duplicate error message"
+	l.Info().Logf("account %s not found", id)
+	l.Log("retrying lookup")
+}
+
+type service struct {
+	l moovlog.Logger
+}
+
+func (s *service) lookup(id string) {
+	s.l.Logf("lookup of %s timed out", id) // want "duplicate error message"
+	s.l.LogErrorf("lookup of %s timed out", id)
+}
@@ -0,0 +1,44 @@
+package tests
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+)
+
+type FieldError struct {
+	Msg   string
+	Field string
+}
+
+func (e FieldError) Error() string {
+	return e.Field + ": " + e.Msg
+}
+
+func NewFieldError(msg string, field string) error {
+	return FieldError{Msg: msg, Field: field}
+}
+
+func NewCodedError(code int, msg string) error {
+	return fmt.Errorf("%d: %s", code, msg)
+}
+
+func builtMessages(name string) {
+	var b strings.Builder
+	b.WriteString("invalid value for ")
+	b.WriteString(name)
+
+	// Messages built at runtime can't be compared
+	errors.New(b.String())
+	errors.New(b.String())
+	fmt.Errorf(b.String())
+	fmt.Errorf(b.String())
+
+	// The dynamic message comes first, so the field name isn't the message
+	NewFieldError(b.String(), "email")
+	NewFieldError(b.String(), "email")
+
+	// Literal messages after a non-string argument are still found
+	NewCodedError(409, "record already exists") // want "duplicate error message"
+	NewCodedError(412, "record already exists")
+}
@@ -0,0 +1,21 @@
+package tests
+
+import (
+	"github.com/spf13/cobra"
+)
+
+func cobraCommand() *cobra.Command {
+	return &cobra.Command{
+		Use: "sync",
+		RunE: func(cmd *cobra.Command, args []string) error {
+			cmd.PrintErrf("unable to reach %s", args[0]) // want "duplicate error message"
+			cmd.PrintErrf("unable to reach %v", args[1])
+			cmd.PrintErrln("missing sync target") // want "duplicate error message"
+			cmd.PrintErr("missing sync target")
+			// Without formatting the verbs are literal text and stay distinct
+			cmd.PrintErr("printed with %s")
+			cmd.PrintErrln("printed with %v")
+			return nil
+		},
+	}
+}
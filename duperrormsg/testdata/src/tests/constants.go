@@ -0,0 +1,59 @@
+package tests
+
+import (
+	"errors"
+	"fmt"
+	"net/http"
+)
+
+const msgTimeout = "operation timed out"
+
+const (
+	msgUnavailable        = "service unavailable"
+	msgRetry       string = "retry after %d seconds"
+)
+
+var msgDynamic = "operation timed out"
+
+func constantMessages(seconds int) error {
+	// Constants referenced by name match the same message spelled inline
+	if seconds < 0 {
+		return errors.New(msgTimeout) // want "duplicate error message"
+	}
+	if seconds == 0 {
+		return errors.New("operation timed out")
+	}
+
+	// Typed constants and format strings are resolved too
+	fmt.Errorf(msgRetry, seconds) // want "duplicate error message"
+	fmt.Errorf("retry after %v seconds", seconds)
+
+	// Constants from other packages are resolved through their selector
+	errors.New(http.MethodGet) // want "duplicate error message"
+	errors.New("GET")
+
+	// Variables could change at runtime and are skipped
+	errors.New(msgDynamic)
+	errors.New(msgDynamic)
+
+	return errors.New(msgUnavailable)
+}
+
+const prefix = "auth"
+
+func prefixedMessages(user string) error {
+	// Constant operands of a concatenation are folded along with literals
+	if user == "" {
+		return errors.New(prefix + ": not found") // want "duplicate error message"
+	}
+	if user == "root" {
+		return errors.New("auth: not found")
+	}
+
+	// Format strings too, so verbs still match their spelled out form
+	fmt.Errorf(prefix+": user %s locked", user) // want "duplicate error message"
+	fmt.Errorf("auth: user %q locked", user)
+
+	// A single operand known only at runtime aborts the fold
+	return errors.New(prefix + ": " + user + " not found")
+}
@@ -0,0 +1,23 @@
+package tests
+
+import (
+	"context"
+	"errors"
+	"time"
+)
+
+type worker struct {
+	failWith context.CancelCauseFunc
+}
+
+func cancelCauses(ctx context.Context) {
+	ctx, cancel := context.WithCancelCause(ctx)
+	cancel(errors.New("worker shutdown requested")) // want "duplicate error message"
+
+	w := worker{failWith: cancel}
+	w.failWith(errors.New("worker shutdown requested"))
+
+	_, stop := context.WithTimeoutCause(ctx, time.Second, errors.New("worker deadline exceeded")) // want "duplicate error message"
+	defer stop()
+	cancel(errors.New("worker deadline exceeded"))
+}
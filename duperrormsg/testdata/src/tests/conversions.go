@@ -0,0 +1,19 @@
+package tests
+
+import (
+	"fmt"
+)
+
+type format string
+
+const notFoundFormat format = "record %s not found"
+
+func findRecord(id string, dynamic format) {
+	// A converted typed constant compares equal to the inline format string
+	fmt.Errorf(string(notFoundFormat), id) // want `duplicate error message "record %s not found" used at 2 locations`
+	fmt.Errorf("record %v not found", id)
+
+	// Conversions of values only known at runtime are skipped
+	fmt.Errorf(string(dynamic), id)
+	fmt.Errorf(string(dynamic), id)
+}
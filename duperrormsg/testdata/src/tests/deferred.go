@@ -0,0 +1,23 @@
+package tests
+
+import (
+	"fmt"
+)
+
+func commitTx() (err error) {
+	defer func() {
+		if err != nil {
+			err = fmt.Errorf("closing transaction: %w", err) // want "duplicate error message"
+		}
+	}()
+	return nil
+}
+
+func rollbackTx() (err error) {
+	defer func() {
+		if err != nil {
+			err = fmt.Errorf("closing transaction: %w", err)
+		}
+	}()
+	return nil
+}
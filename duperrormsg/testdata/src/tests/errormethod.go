@@ -0,0 +1,32 @@
+package tests
+
+import (
+	"errors"
+	"fmt"
+)
+
+type MyError struct {
+	Msg string
+}
+
+func (e MyError) Error() string {
+	return e.Msg
+}
+
+func errorMethod(id int) error {
+	// The inner fmt.Errorf is checked, the .Error() call reading it back isn't
+	if id < 0 {
+		return MyError{Msg: fmt.Errorf("invalid id %d", id).Error()} // want "duplicate error message"
+	}
+	if id == 0 {
+		return MyError{Msg: fmt.Errorf("invalid id %d", id).Error()}
+	}
+
+	err := errors.New("lookup failed")
+	return MyError{Msg: err.Error()}
+}
+
+func errorMethods(err error, e MyError) []string {
+	// Reading messages back never constructs anything
+	return []string{err.Error(), err.Error(), e.Error(), e.Error()}
+}
@@ -0,0 +1,41 @@
+package tests
+
+import (
+	"errors"
+	"fmt"
+)
+
+func assignThenPass(name string, retries int) error {
+	// A local assigned once is resolved to its value, here a Sprintf format
+	msg := fmt.Sprintf("user %s suspended", name)
+	if retries > 3 {
+		return errors.New(msg) // want "duplicate error message"
+	}
+	return fmt.Errorf("user %v suspended", name)
+}
+
+func declaredThenPass() error {
+	var reason = "quota reached"
+	var other string = "quota reached"
+	errors.New(reason) // want "duplicate error message"
+	return errors.New(other)
+}
+
+func reassigned(name string, verbose bool) error {
+	// Locals assigned more than once could hold anything and are skipped
+	msg := "session expired"
+	if verbose {
+		msg = fmt.Sprintf("session of %s expired", name)
+	}
+	errors.New(msg)
+
+	text := "session expired"
+	ptr := &text
+	*ptr = "session renewed"
+	errors.New(text)
+
+	for _, s := range []string{"session expired"} {
+		errors.New(s)
+	}
+	return errors.New("session expired")
+}
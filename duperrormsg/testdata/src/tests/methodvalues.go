@@ -0,0 +1,22 @@
+package tests
+
+type handler struct{}
+
+func (handler) NewError(msg string) error { return nil }
+
+func (handler) failWith(msg string) error { return nil }
+
+func newHandler() *handler { return &handler{} }
+
+func methodConstructors(h *handler, handlers []handler) {
+	// Methods named like error builders match on any value, not just on
+	// identifiers
+	h.NewError("handler crashed") // want `duplicate error message "handler crashed" used at 4 locations \(NewError\)$`
+	h.NewError("handler crashed")
+	handlers[0].NewError("handler crashed")
+	newHandler().NewError("handler crashed")
+
+	h.failWith("handler stopped") // want `duplicate error message "handler stopped" used at 3 locations \(failWith\)$`
+	(*h).failWith("handler stopped")
+	handlers[len(handlers)-1].failWith("handler stopped")
+}
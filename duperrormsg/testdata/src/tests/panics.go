@@ -0,0 +1,38 @@
+package tests
+
+import (
+	"errors"
+	"fmt"
+)
+
+func transition(state int) {
+	switch state {
+	case 0:
+		panic("boom") // want `duplicate error message "boom" used at 2 locations`
+	case 1:
+		panic("boom")
+	case 2:
+		// The message belongs to fmt.Errorf, so it isn't counted twice
+		panic(fmt.Errorf("invalid state %d", state)) // want `duplicate error message "invalid state %d" used at 2 locations`
+	case 3:
+		panic(fmt.Errorf("invalid state %v", state))
+	case 4:
+		panic(errors.New("unreachable"))
+	}
+}
+
+func panicShadowed() {
+	// A local function named panic isn't the builtin
+	panic := func(msg string) {}
+	panic("stopped")
+	panic("stopped")
+}
+
+func panicFormats(input string) {
+	// A formatted string panic matches the same format wrapped in an error,
+	// each counted once
+	if input == "" {
+		panic(fmt.Sprintf("bad input %q", input)) // want `duplicate error message "bad input %q" used at 2 locations \(mixed constructs\)$`
+	}
+	panic(fmt.Errorf("bad input %s", input))
+}
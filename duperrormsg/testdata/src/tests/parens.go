@@ -0,0 +1,14 @@
+package tests
+
+import (
+	"errors"
+	"fmt"
+)
+
+func parenthesized() {
+	errors.New(("record locked")) // want "duplicate error message"
+	errors.New(("record locked"))
+
+	fmt.Errorf(("lease %s expired"), "a") // want "duplicate error message"
+	fmt.Errorf(("lease " + ("%v expired")), "b")
+}
@@ -0,0 +1,24 @@
+package tests
+
+import (
+	"errors"
+	"fmt"
+	"strconv"
+	"strings"
+)
+
+func runtimeMessages(code int, parts []string) {
+	// Messages computed at runtime are skipped, even when they look alike
+	errors.New(strconv.Itoa(code))
+	errors.New(strconv.Itoa(code))
+	errors.New(strconv.Itoa(len(parts)))
+	errors.New(strings.Join(parts, ", "))
+	errors.New(strings.Join(parts, ", "))
+	fmt.Errorf(strconv.Quote("code"))
+	fmt.Errorf(strconv.Quote("code"))
+
+	// Nor is part of a concatenation with a call taken on its own
+	errors.New("status " + strconv.Itoa(code))
+	errors.New("status " + strconv.Itoa(code))
+	errors.New("status ")
+}
@@ -0,0 +1,33 @@
+package tests
+
+import (
+	"fmt"
+	"log"
+)
+
+type reporter struct{}
+
+func (reporter) Errorf(format string, args ...interface{}) error {
+	return fmt.Errorf(format, args...)
+}
+
+type deps struct {
+	errs reporter
+	out  *log.Logger
+}
+
+type server struct {
+	deps deps
+	out  *log.Logger
+}
+
+func chainedSelectors(s *server, a struct{ b reporter }) {
+	// Receivers reached through another selector are matched too
+	a.b.Errorf("quota exceeded for %s", "a") // want "duplicate error message"
+	s.deps.errs.Errorf("quota exceeded for %v", "b")
+
+	// Loggers are recognized by the type of the field when its name doesn't
+	// say so
+	s.out.Printf("draining connections") // want "duplicate error message"
+	s.deps.out.Println("draining connections")
+}
@@ -0,0 +1,16 @@
+package tests
+
+import (
+	"errors"
+)
+
+var ErrNotFound = errors.New("not found") // want `duplicate error message "not found" used by ErrNotFound and ErrMissing`
+var ErrMissing = errors.New("not found")
+
+var (
+	ErrTimeout, ErrDeadline = errors.New("request timed out"), errors.New("deadline reached") // want `duplicate error message "request timed out" used at 2 locations`
+)
+
+func requestTimeout() error {
+	return errors.New("request timed out")
+}
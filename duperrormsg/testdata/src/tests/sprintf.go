@@ -0,0 +1,19 @@
+package tests
+
+import (
+	"errors"
+	"fmt"
+)
+
+func banned(name string, days int) {
+	// errors.New(fmt.Sprintf(...)) is compared by its format string
+	errors.New(fmt.Sprintf("user %s banned", name)) // want "duplicate error message"
+	fmt.Errorf("user %v banned", name)
+
+	errors.New((fmt.Sprintf("ban lifted after %d days", days))) // want "duplicate error message"
+	errors.New(fmt.Sprintf("ban lifted after %d days", days))
+
+	// Other formatting functions aren't folded
+	errors.New(fmt.Sprint("user banned"))
+	errors.New(fmt.Sprint("user banned"))
+}
@@ -0,0 +1,12 @@
+package tests
+
+import (
+	"errors"
+	"testing"
+)
+
+func TestSkipped(t *testing.T) {
+	// Test files are only checked with -include-tests
+	errors.New("fixture missing")
+	errors.New("fixture missing")
+}
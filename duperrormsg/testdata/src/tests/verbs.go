@@ -0,0 +1,34 @@
+package tests
+
+import (
+	"errors"
+	"fmt"
+)
+
+func verbForms(name string, width int, ratio float64) {
+	// Explicit argument indexes are part of the verb
+	fmt.Errorf("table %[1]s has no column %[2]s", name, "id") // want "duplicate error message"
+	fmt.Errorf("table %s has no column %s", name, "id")
+
+	// As are flags, widths and precisions, including ones taken from arguments
+	fmt.Errorf("ratio %[2]*d out of range", width, 3) // want "duplicate error message"
+	fmt.Errorf("ratio %-10.4f out of range", ratio)
+	fmt.Errorf("ratio %+.*f out of range", width, ratio)
+	fmt.Errorf("ratio % x out of range", name)
+
+	// Text right after a verb isn't part of it
+	fmt.Errorf("unknown %sfield", name)
+	fmt.Errorf("unknown %sflag", name)
+
+	// An escaped percent sign is literal text, not the start of a verb
+	fmt.Errorf("disk 100%%s full")
+	fmt.Errorf("disk 100%v full", name)
+	fmt.Errorf("quota at 90%% for %s", name) // want "duplicate error message"
+	fmt.Errorf("quota at 90%% for %v", name)
+
+	// Only format strings have verbs, elsewhere a percent sign is literal text
+	errors.New("unsupported codec %s")
+	fmt.Errorf("unsupported codec %d", width)
+	errors.New("unsupported codec %v") // want `duplicate error message "unsupported codec %v" used at 2 locations`
+	errors.New("unsupported codec %v")
+}
@@ -0,0 +1,15 @@
+package tests
+
+import (
+	"fmt"
+)
+
+func loadConfig(err, err2 error) {
+	// Wrapping the same skeleton twice is a duplicate
+	fmt.Errorf("loading config: %w", err) // want `duplicate error message "loading config: %w" used at 2 locations`
+	fmt.Errorf("loading config: %w", err2)
+
+	// %v only formats the error, so it's a different message from %w
+	fmt.Errorf("loading config: %v", err) // want `duplicate error message "loading config: %v" used at 2 locations`
+	fmt.Errorf("loading config: %s", err)
+}
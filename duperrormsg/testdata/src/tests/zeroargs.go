@@ -0,0 +1,17 @@
+package tests
+
+// variadicLogger accepts calls without any arguments, which type check but
+// carry no message
+type variadicLogger struct{}
+
+func (l variadicLogger) Info() variadicLogger          { return l }
+func (l variadicLogger) Logf(args ...interface{})      {}
+func (l variadicLogger) LogErrorf(args ...interface{}) {}
+
+func zeroArgs(l variadicLogger) {
+	// Neither panics nor reports anything
+	l.Info().Logf()
+	l.Info().Logf()
+	l.Info().LogErrorf()
+	l.Info().LogErrorf()
+}
@@ -0,0 +1,19 @@
+package textless
+
+import (
+	"errors"
+	"fmt"
+)
+
+func wrap(err error) {
+	// Placeholders and punctuation alone aren't worth reporting
+	fmt.Errorf("%w", err)
+	fmt.Errorf("%w", err)
+	errors.New(": %s")
+	errors.New(": %s")
+	fmt.Errorf("%d: %v", 1, err)
+	fmt.Errorf("%d: %v", 2, err)
+
+	fmt.Errorf("retry: %w", err) // want `duplicate error message "retry: %w" used at 2 locations`
+	fmt.Errorf("retry: %w", err)
+}
@@ -0,0 +1,13 @@
+package threshold
+
+import "errors"
+
+func sync() {
+	// Used twice, below the threshold of 3
+	errors.New("sync skipped")
+	errors.New("sync skipped")
+
+	errors.New("sync failed") // want `duplicate error message "sync failed" used at 3 locations`
+	errors.New("sync failed")
+	errors.New("sync failed")
+}
@@ -0,0 +1,31 @@
+package trailingparens
+
+import (
+	"errors"
+	"fmt"
+)
+
+func charge(status int) error {
+	// Messages differing only in a trailing code are duplicates, shown as
+	// first written
+	switch status {
+	case 402:
+		return errors.New("payment declined (code 402)") // want `^duplicate error message "payment declined \(code 402\)" used at 3 locations \(mixed constructs\)$`
+	case 403:
+		return errors.New("payment declined (code 403)")
+	case 404:
+		return fmt.Errorf("payment declined (code %d)", status)
+	}
+
+	// Only the final segment is removed
+	switch status {
+	case 500:
+		return errors.New("refund (partial) failed")
+	case 501:
+		return errors.New("refund (full) failed")
+	case 502:
+		// A message that's only parentheses is kept
+		return errors.New("(unknown)")
+	}
+	return errors.New("(none)")
+}
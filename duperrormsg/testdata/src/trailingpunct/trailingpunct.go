@@ -0,0 +1,40 @@
+package trailingpunct
+
+import (
+	"errors"
+	"fmt"
+)
+
+func sync(name string) error {
+	// A trailing period, colon or exclamation mark is ignored, and the
+	// message is displayed as first written
+	if name == "" {
+		return errors.New("operation failed.") // want `duplicate error message "operation failed\." used at 3 locations`
+	}
+	if name == "-" {
+		return errors.New("operation failed")
+	}
+	if name == "!" {
+		// Whitespace is normalized first
+		return errors.New("operation failed! ")
+	}
+
+	if name == "*" {
+		return fmt.Errorf("sync %s:", name) // want `duplicate error message "sync %s:" used at 2 locations`
+	}
+	if name == "." {
+		return fmt.Errorf("sync %v", name)
+	}
+
+	// Punctuation anywhere else still counts, and only one mark is removed
+	if name == "?" {
+		return errors.New("invalid name: empty")
+	}
+	if name == "~" {
+		return errors.New("invalid name empty")
+	}
+	if name == "#" {
+		return errors.New("retrying..")
+	}
+	return errors.New("retrying")
+}
@@ -0,0 +1,14 @@
+package transforms
+
+import (
+	"errors"
+	"fmt"
+)
+
+func charge() {
+	errors.New("Billing: card declined") // want "duplicate error message"
+	fmt.Errorf("card declined ")
+	errors.New("billing: Card Declined  ")
+
+	errors.New("card expired")
+}
@@ -0,0 +1,20 @@
+package whitespace
+
+import (
+	"errors"
+	"fmt"
+)
+
+func connect(host string) {
+	errors.New("failed to connect") // want "duplicate error message"
+	fmt.Errorf("failed to connect ")
+	errors.New("failed  to connect")
+
+	// A tab and a trailing newline, written in raw strings
+	fmt.Errorf(`resolve	%s`, host) // want "duplicate error message"
+	fmt.Errorf(
+		`resolve %s
+`, host)
+
+	errors.New("failed to reconnect")
+}
@@ -0,0 +1,10 @@
+package window
+
+import (
+	"errors"
+)
+
+// Occurrences in other files are never within the window
+func other() error {
+	return errors.New("negative value")
+}
@@ -0,0 +1,36 @@
+package window
+
+import (
+	"errors"
+)
+
+func validate(a, b, c int) error {
+	if a < 0 {
+		return errors.New("negative value") // want `duplicate error message "negative value" used at 3 locations`
+	}
+	if b < 0 {
+		return errors.New("negative value")
+	}
+	if c < 0 {
+		return errors.New("negative value")
+	}
+	return nil
+}
+
+func parse(s string) error {
+	// Too far from the block above to be grouped with it, but close to the
+	// next occurrence
+	if s == "" {
+		return errors.New("negative value") // want `duplicate error message "negative value" used at 2 locations`
+	}
+	return errors.New("negative value")
+}
+
+func first() error {
+	return errors.New("empty input")
+}
+
+// Occurrences more than three lines apart aren't compared
+func second() error {
+	return errors.New("empty input")
+}
@@ -0,0 +1,19 @@
+package windowsimilar
+
+import "errors"
+
+func dial(addr string) error {
+	// Only this cluster's own spelling is listed, and the fix is offered
+	if addr == "" {
+		return errors.New("could not connect to db") // want `^duplicate error message "could not connect to db" used at 2 locations \(errors\.New\)$`
+	}
+	return errors.New("could not connect to db")
+}
+
+func redial(addr string) error {
+	// Both spellings are used within this cluster
+	if addr == "" {
+		return errors.New("couldn't connect to db") // want `^similar error messages "couldn't connect to db" and "could not connect to db" used at 2 locations \(errors\.New\)$`
+	}
+	return errors.New("could not connect to db")
+}
@@ -0,0 +1,21 @@
+package wordorder
+
+import (
+	"errors"
+	"fmt"
+)
+
+func lookup(user, org string) {
+	// The same words in a different order are duplicates
+	fmt.Errorf("user %s org %s", user, org) // want `duplicate error message "user %s org %s" used at 2 locations`
+	fmt.Errorf("org %s user %s", org, user)
+
+	// Punctuation moves along with the word it's attached to
+	errors.New("invalid token: expired") // want `duplicate error message "invalid token: expired" used at 2 locations`
+	errors.New("expired invalid token:")
+	errors.New("expired: invalid token")
+
+	// Repeated words still have to appear as often
+	errors.New("not found")
+	errors.New("not not found")
+}